@@ -1,6 +1,10 @@
 package poker
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 type Suit string
 
@@ -11,6 +15,23 @@ const (
 	Heart   Suit = "h"
 )
 
+var suits = []Suit{Spade, Club, Diamond, Heart}
+
+func (s Suit) index() int {
+	switch s {
+	case Spade:
+		return 0
+	case Club:
+		return 1
+	case Diamond:
+		return 2
+	case Heart:
+		return 3
+	default:
+		return -1
+	}
+}
+
 type CardRank int
 
 const (
@@ -29,14 +50,150 @@ const (
 	Deuce
 )
 
+const rankChars = "23456789TJQKA"
+
+func (r CardRank) valid() bool {
+	return Deuce <= r && r <= Ace
+}
+
 type Card struct {
 	Suit Suit
 	Rank CardRank
 }
 
+func ParseCard(s string) (Card, error) {
+	if len(s) != 2 {
+		return Card{}, fmt.Errorf("poker: invalid card %q", s)
+	}
+	r := strings.IndexByte(rankChars, strings.ToUpper(s[:1])[0])
+	if r < 0 {
+		return Card{}, fmt.Errorf("poker: invalid card rank in %q", s)
+	}
+	suit := Suit(strings.ToLower(s[1:]))
+	if suit.index() < 0 {
+		return Card{}, fmt.Errorf("poker: invalid card suit in %q", s)
+	}
+	return Card{Suit: suit, Rank: Deuce + CardRank(r)}, nil
+}
+
+func ParseCards(s string) ([]Card, error) {
+	fields := strings.Fields(s)
+	// also accept concatenated cards such as "AsKd"
+	if len(fields) == 1 && len(fields[0]) > 2 && len(fields[0])%2 == 0 {
+		f := fields[0]
+		fields = fields[:0]
+		for i := 0; i < len(f); i += 2 {
+			fields = append(fields, f[i:i+2])
+		}
+	}
+	cards := make([]Card, 0, len(fields))
+	for _, f := range fields {
+		c, err := ParseCard(f)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
 func (c Card) String() string {
 	if c.Rank == Ace {
 		return fmt.Sprintf("%d%s", 1, c.Suit)
 	}
 	return fmt.Sprintf("%d%s", c.Rank, c.Suit)
 }
+
+func (c Card) valid() bool {
+	return c.Rank.valid() && c.Suit.index() >= 0
+}
+
+// index maps the card onto 0..51, ordered by suit and then rank.
+func (c Card) index() int {
+	return c.Suit.index()*13 + int(c.Rank-Deuce)
+}
+
+func cardFromIndex(i int) Card {
+	return Card{Suit: suits[i/13], Rank: Deuce + CardRank(i%13)}
+}
+
+func (c Card) MarshalText() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("poker: invalid card {%q %d}", c.Suit, c.Rank)
+	}
+	return []byte{rankChars[c.Rank-Deuce], c.Suit[0]}, nil
+}
+
+func (c *Card) UnmarshalText(text []byte) error {
+	parsed, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+func (c Card) MarshalJSON() ([]byte, error) {
+	text, err := c.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+func (c Card) MarshalBinary() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("poker: invalid card {%q %d}", c.Suit, c.Rank)
+	}
+	return []byte{byte(c.index())}, nil
+}
+
+func (c *Card) UnmarshalBinary(data []byte) error {
+	if len(data) != 1 || data[0] >= 52 {
+		return fmt.Errorf("poker: invalid binary card %v", data)
+	}
+	*c = cardFromIndex(int(data[0]))
+	return nil
+}
+
+func formatCards(cards []Card) (string, error) {
+	fields := make([]string, 0, len(cards))
+	for _, c := range cards {
+		text, err := c.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, string(text))
+	}
+	return strings.Join(fields, " "), nil
+}
+
+func marshalCardsBinary(cards []Card) ([]byte, error) {
+	data := make([]byte, 0, len(cards))
+	for _, c := range cards {
+		b, err := c.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+func unmarshalCardsBinary(data []byte) ([]Card, error) {
+	cards := make([]Card, len(data))
+	for i := range data {
+		if err := cards[i].UnmarshalBinary(data[i : i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return cards, nil
+}
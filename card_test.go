@@ -0,0 +1,144 @@
+package poker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    Card
+		wantErr bool
+	}{
+		{name: "ace", s: "As", want: Card{Suit: Spade, Rank: Ace}},
+		{name: "ten", s: "Td", want: Card{Suit: Diamond, Rank: Ten}},
+		{name: "deuce", s: "2c", want: Card{Suit: Club, Rank: Deuce}},
+		{name: "lower case", s: "kh", want: Card{Suit: Heart, Rank: King}},
+		{name: "invalid rank", s: "1s", wantErr: true},
+		{name: "invalid suit", s: "Ax", wantErr: true},
+		{name: "too long", s: "10s", wantErr: true},
+		{name: "empty", s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCard(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatalf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseCards(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []Card
+		wantErr bool
+	}{
+		{
+			name: "separated",
+			s:    "As Kd  Qh",
+			want: []Card{{Suit: Spade, Rank: Ace}, {Suit: Diamond, Rank: King}, {Suit: Heart, Rank: Queen}},
+		},
+		{
+			name: "concatenated",
+			s:    "AsKd",
+			want: []Card{{Suit: Spade, Rank: Ace}, {Suit: Diamond, Rank: King}},
+		},
+		{
+			name:    "invalid",
+			s:       "As Kx",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCards(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatalf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCard_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cards := []Card{{Suit: Spade, Rank: Ace}, {Suit: Club, Rank: Ten}, {Suit: Heart, Rank: Deuce}}
+	b, err := json.Marshal(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["As","Tc","2h"]`; string(b) != want {
+		t.Fatalf("want %s, but got %s", want, b)
+	}
+
+	var got []Card
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, cards); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+
+	if _, err := json.Marshal(Card{Suit: "x", Rank: Ace}); err == nil {
+		t.Fatalf("invalid card was marshaled")
+	}
+	if err := json.Unmarshal([]byte(`"Zz"`), &Card{}); err == nil {
+		t.Fatalf("invalid card was unmarshaled")
+	}
+}
+
+func TestCard_MarshalBinary(t *testing.T) {
+	t.Parallel()
+
+	seen := map[byte]bool{}
+	d := &Deck{}
+	d.Reset()
+	for _, c := range d.Cards {
+		b, err := c.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 1 {
+			t.Fatalf("card %v encoded to %d bytes", c, len(b))
+		}
+		if seen[b[0]] {
+			t.Fatalf("card %v encoded to duplicated byte %d", c, b[0])
+		}
+		seen[b[0]] = true
+
+		var got Card
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if got != c {
+			t.Fatalf("want %v, but got %v", c, got)
+		}
+	}
+
+	if err := (&Card{}).UnmarshalBinary([]byte{52}); err == nil {
+		t.Fatalf("invalid byte was unmarshaled")
+	}
+}
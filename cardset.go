@@ -0,0 +1,127 @@
+package poker
+
+import "math/bits"
+
+// CardSet is a set of cards packed into a 64-bit mask. Each suit occupies 16
+// bits, of which the low 13 hold deuce through ace.
+type CardSet uint64
+
+const FullCardSet CardSet = 0x1fff1fff1fff1fff
+
+func cardBit(c Card) CardSet {
+	return 1 << (uint(c.Suit.index())*16 + uint(c.Rank-Deuce))
+}
+
+func NewCardSet(cards ...Card) CardSet {
+	var cs CardSet
+	for _, c := range cards {
+		cs = cs.Add(c)
+	}
+	return cs
+}
+
+func (cs CardSet) Add(c Card) CardSet {
+	if !c.valid() {
+		return cs
+	}
+	return cs | cardBit(c)
+}
+
+func (cs CardSet) Remove(c Card) CardSet {
+	if !c.valid() {
+		return cs
+	}
+	return cs &^ cardBit(c)
+}
+
+func (cs CardSet) Contains(c Card) bool {
+	return c.valid() && cs&cardBit(c) != 0
+}
+
+func (cs CardSet) ContainsAll(other CardSet) bool {
+	return cs&other == other
+}
+
+func (cs CardSet) Overlaps(other CardSet) bool {
+	return cs&other != 0
+}
+
+func (cs CardSet) Union(other CardSet) CardSet {
+	return cs | other
+}
+
+func (cs CardSet) Intersect(other CardSet) CardSet {
+	return cs & other
+}
+
+func (cs CardSet) Difference(other CardSet) CardSet {
+	return cs &^ other
+}
+
+func (cs CardSet) Complement() CardSet {
+	return FullCardSet &^ cs
+}
+
+func (cs CardSet) Len() int {
+	return bits.OnesCount64(uint64(cs))
+}
+
+func (cs CardSet) IsEmpty() bool {
+	return cs == 0
+}
+
+// suitMask returns the ranks held in suit s as a 13-bit mask with the deuce
+// in the lowest bit.
+func (cs CardSet) suitMask(s int) uint16 {
+	return uint16(cs>>(uint(s)*16)) & 0x1fff
+}
+
+// Each calls f for every card in the set, from the lowest suit and rank up,
+// and stops early when f returns false.
+func (cs CardSet) Each(f func(Card) bool) {
+	for cs != 0 {
+		i := bits.TrailingZeros64(uint64(cs))
+		cs &= cs - 1
+		if !f(Card{Suit: suits[i/16], Rank: Deuce + CardRank(i%16)}) {
+			return
+		}
+	}
+}
+
+func (cs CardSet) Cards() []Card {
+	cards := make([]Card, 0, cs.Len())
+	cs.Each(func(c Card) bool {
+		cards = append(cards, c)
+		return true
+	})
+	return cards
+}
+
+func (cs CardSet) Deck() *Deck {
+	return &Deck{Cards: cs.Cards()}
+}
+
+func (cs CardSet) Board() Board {
+	return Board{Cards: cs.Cards()}
+}
+
+func (cs CardSet) PersonalHand() PersonalHand {
+	return PersonalHand{Cards: cs.Cards()}
+}
+
+func (cs CardSet) String() string {
+	s, _ := formatCards(cs.Cards())
+	return "[" + s + "]"
+}
+
+func (d *Deck) CardSet() CardSet {
+	return NewCardSet(d.Cards...)
+}
+
+func (b Board) CardSet() CardSet {
+	return NewCardSet(b.Cards...)
+}
+
+func (ph PersonalHand) CardSet() CardSet {
+	return NewCardSet(ph.Cards...)
+}
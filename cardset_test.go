@@ -0,0 +1,109 @@
+package poker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCardSet_Operations(t *testing.T) {
+	t.Parallel()
+
+	as := Card{Suit: Spade, Rank: Ace}
+	kd := Card{Suit: Diamond, Rank: King}
+	twoC := Card{Suit: Club, Rank: Deuce}
+
+	a := NewCardSet(as, kd)
+	b := NewCardSet(kd, twoC)
+
+	tests := []struct {
+		name string
+		got  CardSet
+		want []Card
+	}{
+		{name: "union", got: a.Union(b), want: []Card{as, twoC, kd}},
+		{name: "intersect", got: a.Intersect(b), want: []Card{kd}},
+		{name: "difference", got: a.Difference(b), want: []Card{as}},
+		{name: "add", got: a.Add(twoC), want: []Card{as, twoC, kd}},
+		{name: "remove", got: a.Remove(as), want: []Card{kd}},
+		{name: "add invalid", got: a.Add(Card{Suit: "x", Rank: Ace}), want: []Card{as, kd}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.got.Cards(), tt.want); diff != "" {
+				t.Fatalf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+
+	if !a.Contains(as) || a.Contains(twoC) {
+		t.Fatalf("Contains is wrong for %v", a)
+	}
+	if !a.Overlaps(b) || a.Overlaps(NewCardSet(twoC)) {
+		t.Fatalf("Overlaps is wrong for %v", a)
+	}
+	if !a.Union(b).ContainsAll(a) || a.ContainsAll(b) {
+		t.Fatalf("ContainsAll is wrong for %v", a)
+	}
+	if got := a.Complement().Len(); got != 50 {
+		t.Fatalf("complement has %d cards", got)
+	}
+}
+
+func TestCardSet_FullDeck(t *testing.T) {
+	t.Parallel()
+
+	d := &Deck{}
+	d.Reset()
+	cs := d.CardSet()
+	if cs != FullCardSet {
+		t.Fatalf("want %x, but got %x", uint64(FullCardSet), uint64(cs))
+	}
+	if cs.Len() != 52 {
+		t.Fatalf("want 52 cards, but got %d", cs.Len())
+	}
+	if diff := cmp.Diff(cs.Deck().Cards, d.Cards, cmpopts.SortSlices(func(a, b Card) bool {
+		return a.index() < b.index()
+	})); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestCardSet_Each(t *testing.T) {
+	t.Parallel()
+
+	cs := FullCardSet
+	n := 0
+	cs.Each(func(c Card) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatalf("Each did not stop after 10 cards(%d cards)", n)
+	}
+}
+
+func TestCardSet_Conversions(t *testing.T) {
+	t.Parallel()
+
+	board := Board{Cards: []Card{{Suit: Heart, Rank: Seven}, {Suit: Club, Rank: Seven}, {Suit: Spade, Rank: Deuce}}}
+	hand := PersonalHand{Cards: []Card{{Suit: Spade, Rank: Ace}, {Suit: Spade, Rank: King}}}
+
+	cs := board.CardSet().Union(hand.CardSet())
+	if cs.Len() != 5 {
+		t.Fatalf("want 5 cards, but got %d", cs.Len())
+	}
+	if got := cs.Difference(hand.CardSet()).Board(); !cmp.Equal(got.CardSet(), board.CardSet()) {
+		t.Fatalf("want %v, but got %v", board.Cards, got.Cards)
+	}
+	if got := hand.CardSet().PersonalHand(); len(got.Cards) != 2 || got.CardSet() != hand.CardSet() {
+		t.Fatalf("want %v, but got %v", hand.Cards, got.Cards)
+	}
+	if got, want := cs.String(), "[2s Ks As 7c 7h]"; got != want {
+		t.Fatalf("want %s, but got %s", want, got)
+	}
+}
@@ -0,0 +1,132 @@
+package cfr
+
+// Value returns the expected payoff of player when both players follow st.
+func Value(g Game, st Strategy, player int) float64 {
+	return value(g.Root(), st, player)
+}
+
+func value(h State, st Strategy, player int) float64 {
+	if h.IsTerminal() {
+		return h.Utility(player)
+	}
+	var probs []float64
+	if h.Player() == Chance {
+		probs = h.ChanceProbabilities()
+	} else {
+		probs = st.Probabilities(h.InfoSet(), h.NumActions())
+	}
+	v := 0.0
+	for a, pr := range probs {
+		if pr > 0 {
+			v += pr * value(h.Child(a), st, player)
+		}
+	}
+	return v
+}
+
+type weightedState struct {
+	state  State
+	weight float64
+}
+
+// bestResponse finds the best response of player to the strategy of its
+// opponent lazily: the action of an information set is picked the first
+// time it is needed, from the values of all its states weighted by how
+// likely the opponent and chance reach them.
+type bestResponse struct {
+	st       Strategy
+	player   int
+	infoSets map[string][]weightedState
+	actions  map[string]int
+}
+
+// BestResponseValue returns the expected payoff of the best response of
+// player against the strategy of its opponent in st.
+func BestResponseValue(g Game, st Strategy, player int) float64 {
+	br := &bestResponse{st: st, player: player, infoSets: map[string][]weightedState{}, actions: map[string]int{}}
+	root := g.Root()
+	br.collect(root, 1)
+	return br.value(root)
+}
+
+// Exploitability returns how much a best response gains against st on
+// average over both players. It is zero at an equilibrium.
+func Exploitability(g Game, st Strategy) float64 {
+	return (BestResponseValue(g, st, 0) + BestResponseValue(g, st, 1)) / 2
+}
+
+func (br *bestResponse) collect(h State, weight float64) {
+	if h.IsTerminal() {
+		return
+	}
+	switch p := h.Player(); p {
+	case Chance:
+		for a, pr := range h.ChanceProbabilities() {
+			if pr > 0 {
+				br.collect(h.Child(a), weight*pr)
+			}
+		}
+	case br.player:
+		key := h.InfoSet()
+		br.infoSets[key] = append(br.infoSets[key], weightedState{state: h, weight: weight})
+		for a := 0; a < h.NumActions(); a++ {
+			br.collect(h.Child(a), weight)
+		}
+	default:
+		for a, pr := range br.st.Probabilities(h.InfoSet(), h.NumActions()) {
+			if pr > 0 {
+				br.collect(h.Child(a), weight*pr)
+			}
+		}
+	}
+}
+
+func (br *bestResponse) value(h State) float64 {
+	if h.IsTerminal() {
+		return h.Utility(br.player)
+	}
+	switch p := h.Player(); p {
+	case Chance:
+		v := 0.0
+		for a, pr := range h.ChanceProbabilities() {
+			if pr > 0 {
+				v += pr * br.value(h.Child(a))
+			}
+		}
+		return v
+	case br.player:
+		return br.value(h.Child(br.action(h.InfoSet())))
+	default:
+		v := 0.0
+		for a, pr := range br.st.Probabilities(h.InfoSet(), h.NumActions()) {
+			if pr > 0 {
+				v += pr * br.value(h.Child(a))
+			}
+		}
+		return v
+	}
+}
+
+func (br *bestResponse) action(infoSet string) int {
+	if a, ok := br.actions[infoSet]; ok {
+		return a
+	}
+	states := br.infoSets[infoSet]
+	var values []float64
+	for _, ws := range states {
+		if values == nil {
+			values = make([]float64, ws.state.NumActions())
+		}
+		for a := range values {
+			values[a] += ws.weight * br.value(ws.state.Child(a))
+		}
+	}
+	best := 0
+	for a := range values {
+		if values[a] > values[best] {
+			best = a
+		}
+	}
+	br.actions[infoSet] = best
+	return best
+}
@@ -0,0 +1,287 @@
+// Package cfr finds equilibria of two player zero-sum games with
+// counterfactual regret minimization.
+package cfr
+
+import (
+	"math/rand"
+)
+
+// Chance is the player acting at chance nodes.
+const Chance = -1
+
+// State is a history of a two player zero-sum game. The players are 0 and
+// 1. States must not change once created.
+type State interface {
+	// Player returns the player to act, or Chance.
+	Player() int
+	IsTerminal() bool
+	// Utility returns the payoff of player at a terminal state.
+	Utility(player int) float64
+	// InfoSet returns a key for what the player to act knows. States of
+	// the same information set must have the same number of actions.
+	InfoSet() string
+	// NumActions returns the number of actions, or chance outcomes.
+	NumActions() int
+	Child(action int) State
+	// ChanceProbabilities returns the probability of each outcome of a
+	// chance node.
+	ChanceProbabilities() []float64
+}
+
+type Game interface {
+	Root() State
+}
+
+type Algorithm int
+
+const (
+	// Vanilla walks the whole tree each iteration with alternating updates.
+	Vanilla Algorithm = iota + 1
+	// Plus is CFR+: regrets are floored at zero and later iterations get
+	// more weight in the average strategy.
+	Plus
+	// ExternalSampling is Monte Carlo CFR sampling chance outcomes and the
+	// actions of the opponent of the updated player.
+	ExternalSampling
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case Vanilla:
+		return "CFR"
+	case Plus:
+		return "CFR+"
+	case ExternalSampling:
+		return "external sampling MCCFR"
+	default:
+		return "unknown"
+	}
+}
+
+type infoNode struct {
+	regrets     []float64
+	strategySum []float64
+	// strategy is the strategy of regret matching during pass.
+	strategy []float64
+	pass     int
+}
+
+// current returns the strategy of regret matching, which stays the same
+// for the rest of the pass however often the node is visited.
+func (n *infoNode) current(pass int) []float64 {
+	if n.strategy != nil && n.pass == pass {
+		return n.strategy
+	}
+	s := make([]float64, len(n.regrets))
+	total := 0.0
+	for a, r := range n.regrets {
+		if r > 0 {
+			s[a] = r
+			total += r
+		}
+	}
+	for a := range s {
+		if total > 0 {
+			s[a] /= total
+		} else {
+			s[a] = 1 / float64(len(s))
+		}
+	}
+	n.strategy, n.pass = s, pass
+	return s
+}
+
+type Solver struct {
+	game       Game
+	algorithm  Algorithm
+	rnd        *rand.Rand
+	nodes      map[string]*infoNode
+	iterations int
+	// pass counts the walks of the tree, one per player each iteration.
+	pass int
+}
+
+// NewSolver returns a solver of g. rnd is only used by the sampling
+// algorithms.
+func NewSolver(g Game, a Algorithm, rnd *rand.Rand) *Solver {
+	return &Solver{game: g, algorithm: a, rnd: rnd, nodes: map[string]*infoNode{}}
+}
+
+func (s *Solver) Iterations() int {
+	return s.iterations
+}
+
+// Run runs the given number of iterations, each updating both players.
+func (s *Solver) Run(iterations int) {
+	for i := 0; i < iterations; i++ {
+		s.iterations++
+		for p := 0; p < 2; p++ {
+			s.pass++
+			if s.algorithm == ExternalSampling {
+				s.sample(s.game.Root(), p)
+				continue
+			}
+			s.traverse(s.game.Root(), p, [2]float64{1, 1}, 1)
+			if s.algorithm == Plus {
+				// floor the regrets only once all of the pass is added up
+				for _, n := range s.nodes {
+					for a, r := range n.regrets {
+						if r < 0 {
+							n.regrets[a] = 0
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (s *Solver) node(h State) *infoNode {
+	key := h.InfoSet()
+	n, ok := s.nodes[key]
+	if !ok {
+		n = &infoNode{regrets: make([]float64, h.NumActions()), strategySum: make([]float64, h.NumActions())}
+		s.nodes[key] = n
+	}
+	return n
+}
+
+// traverse returns the value of h for traverser and updates its regrets.
+// chance is the probability of the chance outcomes leading to h.
+func (s *Solver) traverse(h State, traverser int, reach [2]float64, chance float64) float64 {
+	if h.IsTerminal() {
+		return h.Utility(traverser)
+	}
+	p := h.Player()
+	if p == Chance {
+		v := 0.0
+		for a, pr := range h.ChanceProbabilities() {
+			if pr > 0 {
+				v += pr * s.traverse(h.Child(a), traverser, reach, chance*pr)
+			}
+		}
+		return v
+	}
+
+	n := s.node(h)
+	strategy := n.current(s.pass)
+	if p != traverser {
+		v := 0.0
+		for a, pr := range strategy {
+			// the average strategy of traverser still needs the histories
+			// the opponent never plays into
+			if pr > 0 || reach[traverser] > 0 {
+				r := reach
+				r[p] *= pr
+				v += pr * s.traverse(h.Child(a), traverser, r, chance)
+			}
+		}
+		return v
+	}
+
+	utils := make([]float64, len(strategy))
+	v := 0.0
+	for a, pr := range strategy {
+		r := reach
+		r[p] *= pr
+		utils[a] = s.traverse(h.Child(a), traverser, r, chance)
+		v += pr * utils[a]
+	}
+	weight := reach[p]
+	if s.algorithm == Plus {
+		weight *= float64(s.iterations)
+	}
+	for a := range utils {
+		n.regrets[a] += reach[1-p] * chance * (utils[a] - v)
+		n.strategySum[a] += weight * strategy[a]
+	}
+	return v
+}
+
+// sample returns a sampled value of h for traverser and updates its
+// regrets. The average strategy of the opponent is updated where its
+// actions are sampled.
+func (s *Solver) sample(h State, traverser int) float64 {
+	if h.IsTerminal() {
+		return h.Utility(traverser)
+	}
+	p := h.Player()
+	if p == Chance {
+		return s.sample(h.Child(s.pick(h.ChanceProbabilities())), traverser)
+	}
+
+	n := s.node(h)
+	strategy := n.current(s.pass)
+	if p != traverser {
+		for a, pr := range strategy {
+			n.strategySum[a] += pr
+		}
+		return s.sample(h.Child(s.pick(strategy)), traverser)
+	}
+
+	utils := make([]float64, len(strategy))
+	v := 0.0
+	for a, pr := range strategy {
+		utils[a] = s.sample(h.Child(a), traverser)
+		v += pr * utils[a]
+	}
+	for a := range utils {
+		n.regrets[a] += utils[a] - v
+	}
+	return v
+}
+
+func (s *Solver) pick(probs []float64) int {
+	x := s.rnd.Float64()
+	for a, pr := range probs {
+		if x < pr {
+			return a
+		}
+		x -= pr
+	}
+	// rounding errors
+	for a := len(probs) - 1; a > 0; a-- {
+		if probs[a] > 0 {
+			return a
+		}
+	}
+	return 0
+}
+
+// AverageStrategy returns the average strategy of every information set
+// visited so far, which converges to an equilibrium.
+func (s *Solver) AverageStrategy() Strategy {
+	st := make(Strategy, len(s.nodes))
+	for key, n := range s.nodes {
+		probs := make([]float64, len(n.strategySum))
+		total := 0.0
+		for _, w := range n.strategySum {
+			total += w
+		}
+		for a, w := range n.strategySum {
+			if total > 0 {
+				probs[a] = w / total
+			} else {
+				probs[a] = 1 / float64(len(probs))
+			}
+		}
+		st[key] = probs
+	}
+	return st
+}
+
+// Strategy maps information sets to the probability of each action.
+type Strategy map[string][]float64
+
+// Probabilities returns the probabilities of the n actions of infoSet,
+// which are uniform when the strategy does not cover it.
+func (st Strategy) Probabilities(infoSet string, n int) []float64 {
+	if probs, ok := st[infoSet]; ok && len(probs) == n {
+		return probs
+	}
+	probs := make([]float64, n)
+	for a := range probs {
+		probs[a] = 1 / float64(n)
+	}
+	return probs
+}
@@ -0,0 +1,264 @@
+package cfr
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// rps is rock paper scissors. Player 1 does not see the move of player 0.
+type rps struct {
+	moves []int
+}
+
+func (g rps) Root() State { return rps{} }
+
+func (s rps) Player() int      { return len(s.moves) }
+func (s rps) IsTerminal() bool { return len(s.moves) == 2 }
+func (s rps) InfoSet() string  { return string(rune('0' + len(s.moves))) }
+func (s rps) NumActions() int  { return 3 }
+
+func (s rps) Utility(player int) float64 {
+	u := float64([]int{0, 1, -1}[(s.moves[0]-s.moves[1]+3)%3])
+	if player == 1 {
+		return -u
+	}
+	return u
+}
+
+func (s rps) Child(action int) State {
+	return rps{moves: append(append([]int(nil), s.moves...), action)}
+}
+
+func (s rps) ChanceProbabilities() []float64 { return nil }
+
+// coin is a chance node deciding whether player 0 wins or loses 1 after
+// it picks to play for 1 or 2.
+type coin struct {
+	stake int
+	won   int
+}
+
+func (g coin) Root() State { return coin{} }
+
+func (s coin) Player() int {
+	if s.stake == 0 {
+		return 0
+	}
+	return Chance
+}
+
+func (s coin) IsTerminal() bool { return s.won != 0 }
+func (s coin) InfoSet() string  { return "" }
+func (s coin) NumActions() int  { return 2 }
+
+func (s coin) Utility(player int) float64 {
+	u := float64(s.stake * s.won)
+	if player == 1 {
+		return -u
+	}
+	return u
+}
+
+func (s coin) Child(action int) State {
+	if s.stake == 0 {
+		return coin{stake: action + 1}
+	}
+	return coin{stake: s.stake, won: []int{1, -1}[action]}
+}
+
+func (s coin) ChanceProbabilities() []float64 { return []float64{0.75, 0.25} }
+
+// kuhn is Kuhn poker with the cards 0, 1 and 2, deep enough that a player
+// has to act again after the other.
+type kuhn struct {
+	cards   []int
+	history string
+}
+
+func (g kuhn) Root() State { return kuhn{} }
+
+func (s kuhn) Player() int {
+	if len(s.cards) < 2 {
+		return Chance
+	}
+	return len(s.history) % 2
+}
+
+func (s kuhn) IsTerminal() bool {
+	switch s.history {
+	case "pp", "bp", "bb", "pbp", "pbb":
+		return true
+	default:
+		return false
+	}
+}
+
+func (s kuhn) Utility(player int) float64 {
+	u := 2.0
+	switch s.history {
+	case "bp":
+		u = 1
+	case "pbp":
+		u = -1
+	case "pp":
+		u = 1
+	}
+	if (s.history == "pp" || u == 2) && s.cards[0] < s.cards[1] {
+		u = -u
+	}
+	if player == 1 {
+		return -u
+	}
+	return u
+}
+
+func (s kuhn) InfoSet() string { return string(rune('0'+s.cards[s.Player()])) + s.history }
+
+func (s kuhn) NumActions() int {
+	if s.Player() == Chance {
+		return 3 - len(s.cards)
+	}
+	return 2
+}
+
+func (s kuhn) Child(action int) State {
+	if s.Player() != Chance {
+		return kuhn{cards: s.cards, history: s.history + []string{"p", "b"}[action]}
+	}
+	for c := 0; c < 3; c++ {
+		if len(s.cards) == 1 && s.cards[0] == c {
+			continue
+		}
+		if action == 0 {
+			return kuhn{cards: append(append([]int(nil), s.cards...), c)}
+		}
+		action--
+	}
+	panic("cfr: no such card")
+}
+
+func (s kuhn) ChanceProbabilities() []float64 {
+	probs := make([]float64, s.NumActions())
+	for i := range probs {
+		probs[i] = 1 / float64(len(probs))
+	}
+	return probs
+}
+
+func TestSolver(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		algorithm  Algorithm
+		iterations int
+	}{
+		{algorithm: Vanilla, iterations: 2000},
+		{algorithm: Plus, iterations: 2000},
+		{algorithm: ExternalSampling, iterations: 20000},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.algorithm.String(), func(t *testing.T) {
+			t.Parallel()
+			s := NewSolver(rps{}, tt.algorithm, rand.New(rand.NewSource(1)))
+			s.Run(tt.iterations)
+			if s.Iterations() != tt.iterations {
+				t.Errorf("want is %d iterations, but got %d", tt.iterations, s.Iterations())
+			}
+			st := s.AverageStrategy()
+			for _, key := range []string{"0", "1"} {
+				for a, pr := range st[key] {
+					if math.Abs(pr-1.0/3) > 0.02 {
+						t.Errorf("want is 1/3 for action %d of %s, but got %v", a, key, pr)
+					}
+				}
+			}
+			if e := Exploitability(rps{}, st); e > 0.02 {
+				t.Errorf("want is exploitability about 0, but got %v", e)
+			}
+		})
+	}
+}
+
+func TestSolver_Kuhn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		algorithm  Algorithm
+		iterations int
+		tolerance  float64
+	}{
+		{algorithm: Vanilla, iterations: 5000, tolerance: 0.001},
+		{algorithm: Plus, iterations: 1000, tolerance: 0.001},
+		{algorithm: ExternalSampling, iterations: 100000, tolerance: 0.01},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.algorithm.String(), func(t *testing.T) {
+			t.Parallel()
+			s := NewSolver(kuhn{}, tt.algorithm, rand.New(rand.NewSource(1)))
+			s.Run(tt.iterations)
+			st := s.AverageStrategy()
+			if len(st) != 12 {
+				t.Errorf("want is 12 information sets, but got %d", len(st))
+			}
+			if v := Value(kuhn{}, st, 0); math.Abs(v+1.0/18) > tt.tolerance {
+				t.Errorf("want is value %v, but got %v", -1.0/18, v)
+			}
+			if e := Exploitability(kuhn{}, st); e > tt.tolerance {
+				t.Errorf("want is exploitability about 0, but got %v", e)
+			}
+		})
+	}
+}
+
+func TestBestResponseValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		game   Game
+		st     Strategy
+		player int
+		want   float64
+	}{
+		{
+			name:   "always rock",
+			game:   rps{},
+			st:     Strategy{"0": {1, 0, 0}},
+			player: 1,
+			want:   1,
+		},
+		{
+			name:   "rock or paper",
+			game:   rps{},
+			st:     Strategy{"1": {0.5, 0.5, 0}},
+			player: 0,
+			want:   0.5,
+		},
+		{
+			name:   "chance",
+			game:   coin{},
+			player: 0,
+			want:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := BestResponseValue(tt.game, tt.st, tt.player); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("want is %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+	st := Strategy{"0": {1, 0, 0}, "1": {0, 0.5, 0.5}}
+	if got := Value(rps{}, st, 0); got != 0 {
+		t.Errorf("want is value 0, but got %v", got)
+	}
+	if got := Value(coin{}, Strategy{"": {0, 1}}, 1); got != -1 {
+		t.Errorf("want is value -1, but got %v", got)
+	}
+}
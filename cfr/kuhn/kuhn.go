@@ -0,0 +1,119 @@
+// Package kuhn implements Kuhn poker: each player antes 1 and gets one of
+// a jack, a queen and a king, then there is a single round of betting 1
+// where player 0 acts first.
+package kuhn
+
+import (
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/cfr"
+)
+
+const (
+	Pass = iota
+	Bet
+)
+
+// Value is the expected payoff of player 0 at any equilibrium.
+const Value = -1.0 / 18
+
+// Deck returns the three cards of the game.
+func Deck() *poker.Deck {
+	return poker.NewDeck(
+		poker.Card{Suit: poker.Spade, Rank: poker.Jack},
+		poker.Card{Suit: poker.Spade, Rank: poker.Queen},
+		poker.Card{Suit: poker.Spade, Rank: poker.King},
+	)
+}
+
+type Game struct{}
+
+func (Game) Root() cfr.State {
+	return &state{remaining: Deck().Cards}
+}
+
+// Deal returns the start of a hand with the cards drawn from d.
+func (Game) Deal(d *poker.Deck) cfr.State {
+	return &state{cards: []poker.Card{d.Draw(), d.Draw()}}
+}
+
+type state struct {
+	cards     []poker.Card
+	remaining []poker.Card
+	// history holds "p" for a pass and "b" for a bet.
+	history string
+}
+
+func (s *state) Player() int {
+	if len(s.cards) < 2 {
+		return cfr.Chance
+	}
+	return len(s.history) % 2
+}
+
+func (s *state) IsTerminal() bool {
+	switch s.history {
+	case "pp", "bp", "bb", "pbp", "pbb":
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *state) Utility(player int) float64 {
+	var u float64
+	switch s.history {
+	case "bp":
+		u = 1
+	case "pbp":
+		u = -1
+	case "pp":
+		u = 1
+		if s.cards[0].Rank < s.cards[1].Rank {
+			u = -1
+		}
+	default:
+		u = 2
+		if s.cards[0].Rank < s.cards[1].Rank {
+			u = -2
+		}
+	}
+	if player == 1 {
+		return -u
+	}
+	return u
+}
+
+func (s *state) InfoSet() string {
+	return rankName(s.cards[s.Player()]) + s.history
+}
+
+func (s *state) NumActions() int {
+	if s.Player() == cfr.Chance {
+		return len(s.remaining)
+	}
+	return 2
+}
+
+func (s *state) Child(action int) cfr.State {
+	if s.Player() == cfr.Chance {
+		remaining := append([]poker.Card(nil), s.remaining[:action]...)
+		return &state{
+			cards:     append(append([]poker.Card(nil), s.cards...), s.remaining[action]),
+			remaining: append(remaining, s.remaining[action+1:]...),
+		}
+	}
+	return &state{cards: s.cards, history: s.history + []string{"p", "b"}[action]}
+}
+
+func (s *state) ChanceProbabilities() []float64 {
+	probs := make([]float64, len(s.remaining))
+	for i := range probs {
+		probs[i] = 1 / float64(len(probs))
+	}
+	return probs
+}
+
+func rankName(c poker.Card) string {
+	text, _ := c.MarshalText()
+	return string(text[:1])
+}
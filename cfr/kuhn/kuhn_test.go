@@ -0,0 +1,86 @@
+package kuhn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/cfr"
+)
+
+func TestGame_Solve(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		algorithm  cfr.Algorithm
+		iterations int
+		tolerance  float64
+	}{
+		{algorithm: cfr.Vanilla, iterations: 5000, tolerance: 0.005},
+		{algorithm: cfr.Plus, iterations: 1000, tolerance: 0.005},
+		{algorithm: cfr.ExternalSampling, iterations: 100000, tolerance: 0.01},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.algorithm.String(), func(t *testing.T) {
+			t.Parallel()
+			s := cfr.NewSolver(Game{}, tt.algorithm, rand.New(rand.NewSource(1)))
+			s.Run(tt.iterations)
+			st := s.AverageStrategy()
+			if len(st) != 12 {
+				t.Errorf("want is 12 information sets, but got %d", len(st))
+			}
+			if v := cfr.Value(Game{}, st, 0); math.Abs(v-Value) > tt.tolerance {
+				t.Errorf("want is value %v, but got %v", Value, v)
+			}
+			if e := cfr.Exploitability(Game{}, st); e > tt.tolerance {
+				t.Errorf("want is exploitability about 0, but got %v", e)
+			}
+			// the king always calls a bet and the jack never does
+			if p := st["Kpb"][Bet]; p < 0.99 {
+				t.Errorf("want is Kpb calling 1, but got %v", p)
+			}
+			if p := st["Jb"][Bet]; p > 0.01 {
+				t.Errorf("want is Jb calling 0, but got %v", p)
+			}
+		})
+	}
+}
+
+func TestGame_Deal(t *testing.T) {
+	t.Parallel()
+	d := Deck()
+	d.Shuffle(rand.New(rand.NewSource(1)))
+	first, second := d.Cards[0], d.Cards[1]
+	h := Game{}.Deal(d)
+	if len(d.Cards) != 1 {
+		t.Errorf("want is 1 card left, but got %d", len(d.Cards))
+	}
+	if h.Player() != 0 {
+		t.Errorf("want is player 0, but got %d", h.Player())
+	}
+	h = h.Child(Bet).Child(Bet)
+	if !h.IsTerminal() {
+		t.Fatal("bet and call is not terminal")
+	}
+	want := 2.0
+	if first.Rank < second.Rank {
+		want = -2
+	}
+	if got := h.Utility(0); got != want {
+		t.Errorf("want is utility %v, but got %v", want, got)
+	}
+}
+
+func TestDeck(t *testing.T) {
+	t.Parallel()
+	d := Deck()
+	want := poker.NewCardSet(
+		poker.Card{Suit: poker.Spade, Rank: poker.Jack},
+		poker.Card{Suit: poker.Spade, Rank: poker.Queen},
+		poker.Card{Suit: poker.Spade, Rank: poker.King},
+	)
+	if d.CardSet() != want || len(d.Cards) != 3 {
+		t.Errorf("want is %v, but got %v", want, d.Cards)
+	}
+}
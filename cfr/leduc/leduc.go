@@ -0,0 +1,179 @@
+// Package leduc implements Leduc Hold'em: a deck of two jacks, queens and
+// kings, one private card each and one public card. Both players ante 1 and
+// there are two rounds of betting, before and after the public card, with
+// bets and raises of 2 and 4 and at most two of them a round. A pair with
+// the public card wins, otherwise the higher card.
+package leduc
+
+import (
+	"strings"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/cfr"
+)
+
+// Value is the expected payoff of player 0 at an equilibrium, rounded.
+const Value = -0.0856
+
+const maxRaises = 2
+
+var betSizes = [2]int{2, 4}
+
+// Deck returns the six cards of the game.
+func Deck() *poker.Deck {
+	var cards []poker.Card
+	for _, s := range []poker.Suit{poker.Spade, poker.Heart} {
+		for _, r := range []poker.CardRank{poker.Jack, poker.Queen, poker.King} {
+			cards = append(cards, poker.Card{Suit: s, Rank: r})
+		}
+	}
+	return poker.NewDeck(cards...)
+}
+
+type Game struct{}
+
+func (Game) Root() cfr.State {
+	return &state{remaining: Deck().Cards, bets: [2]int{1, 1}}
+}
+
+// Deal returns the start of a hand with the private and then the public
+// card drawn from d.
+func (Game) Deal(d *poker.Deck) cfr.State {
+	return &state{cards: []poker.Card{d.Draw(), d.Draw(), d.Draw()}, bets: [2]int{1, 1}}
+}
+
+type state struct {
+	// cards holds the private cards of both players and then the public
+	// card.
+	cards     []poker.Card
+	remaining []poker.Card
+	round     int
+	// history holds the actions of each round: "k" check, "b" bet,
+	// "c" call, "r" raise and "f" fold.
+	history [2]string
+	bets    [2]int
+	player  int
+	raises  int
+	facing  bool
+	done    bool
+	folder  int
+}
+
+func (s *state) Player() int {
+	if len(s.cards) < 2+s.round {
+		return cfr.Chance
+	}
+	return s.player
+}
+
+func (s *state) IsTerminal() bool {
+	return s.done
+}
+
+func (s *state) Utility(player int) float64 {
+	var winner int
+	if strings.HasSuffix(s.history[s.round], "f") {
+		winner = 1 - s.folder
+	} else {
+		winner = showdown(s.cards[0], s.cards[1], s.cards[2])
+	}
+	if winner < 0 {
+		return 0
+	}
+	u := float64(s.bets[1-winner])
+	if player != winner {
+		u = -u
+	}
+	return u
+}
+
+// showdown returns the player holding the better card, or -1 for a tie.
+func showdown(c0, c1, public poker.Card) int {
+	switch {
+	case c0.Rank == c1.Rank:
+		return -1
+	case c0.Rank == public.Rank:
+		return 0
+	case c1.Rank == public.Rank:
+		return 1
+	case c0.Rank > c1.Rank:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func (s *state) InfoSet() string {
+	key := rankName(s.cards[s.player])
+	if s.round == 1 {
+		key += rankName(s.cards[2])
+	}
+	return key + "|" + s.history[0] + "/" + s.history[1]
+}
+
+// actions returns the codes of the legal actions.
+func (s *state) actions() string {
+	switch {
+	case !s.facing:
+		return "kb"
+	case s.raises < maxRaises:
+		return "fcr"
+	default:
+		return "fc"
+	}
+}
+
+func (s *state) NumActions() int {
+	if s.Player() == cfr.Chance {
+		return len(s.remaining)
+	}
+	return len(s.actions())
+}
+
+func (s *state) Child(action int) cfr.State {
+	next := *s
+	if s.Player() == cfr.Chance {
+		next.cards = append(append([]poker.Card(nil), s.cards...), s.remaining[action])
+		next.remaining = append(append([]poker.Card(nil), s.remaining[:action]...), s.remaining[action+1:]...)
+		return &next
+	}
+
+	code := s.actions()[action]
+	next.history[s.round] += string(code)
+	next.player = 1 - s.player
+	endRound := false
+	switch code {
+	case 'k':
+		endRound = s.player == 1
+	case 'b', 'r':
+		next.bets[s.player] = s.bets[1-s.player] + betSizes[s.round]
+		next.raises++
+		next.facing = true
+	case 'c':
+		next.bets[s.player] = s.bets[1-s.player]
+		endRound = true
+	case 'f':
+		next.done, next.folder = true, s.player
+	}
+	if endRound {
+		if s.round == 1 {
+			next.done = true
+		} else {
+			next.round, next.player, next.raises, next.facing = 1, 0, 0, false
+		}
+	}
+	return &next
+}
+
+func (s *state) ChanceProbabilities() []float64 {
+	probs := make([]float64, len(s.remaining))
+	for i := range probs {
+		probs[i] = 1 / float64(len(probs))
+	}
+	return probs
+}
+
+func rankName(c poker.Card) string {
+	text, _ := c.MarshalText()
+	return string(text[:1])
+}
@@ -0,0 +1,89 @@
+package leduc
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/cfr"
+)
+
+func TestGame_Solve(t *testing.T) {
+	t.Parallel()
+	s := cfr.NewSolver(Game{}, cfr.Plus, nil)
+	s.Run(300)
+	st := s.AverageStrategy()
+	if len(st) != 288 {
+		t.Errorf("want is 288 information sets, but got %d", len(st))
+	}
+	if v := cfr.Value(Game{}, st, 0); math.Abs(v-Value) > 0.001 {
+		t.Errorf("want is value %v, but got %v", Value, v)
+	}
+	if e := cfr.Exploitability(Game{}, st); e > 0.005 {
+		t.Errorf("want is exploitability about 0, but got %v", e)
+	}
+}
+
+func TestState_Utility(t *testing.T) {
+	t.Parallel()
+	j := poker.Card{Suit: poker.Spade, Rank: poker.Jack}
+	q := poker.Card{Suit: poker.Spade, Rank: poker.Queen}
+	k := poker.Card{Suit: poker.Spade, Rank: poker.King}
+	jh := poker.Card{Suit: poker.Heart, Rank: poker.Jack}
+	tests := []struct {
+		name    string
+		cards   []poker.Card
+		actions string
+		want    float64
+	}{
+		{name: "fold preflop", cards: []poker.Card{j, k, q}, actions: "bf", want: 1},
+		{name: "raise and call", cards: []poker.Card{j, k, q}, actions: "brc kk", want: -5},
+		{name: "pair wins", cards: []poker.Card{j, k, jh}, actions: "kk bc", want: 5},
+		{name: "tie", cards: []poker.Card{j, jh, q}, actions: "kk kbrc", want: 0},
+		{name: "capped raises", cards: []poker.Card{k, q, j}, actions: "brc brc", want: 13},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := Game{}.Deal(poker.NewDeck(tt.cards...))
+			for _, code := range tt.actions {
+				if code == ' ' {
+					continue
+				}
+				a := -1
+				for i, c := range h.(*state).actions() {
+					if c == code {
+						a = i
+					}
+				}
+				if a < 0 {
+					t.Fatalf("%c is not legal after %v", code, h.(*state).history)
+				}
+				h = h.Child(a)
+			}
+			if !h.IsTerminal() {
+				t.Fatalf("%s is not terminal", tt.actions)
+			}
+			if got := h.Utility(0); got != tt.want {
+				t.Errorf("want is utility %v for player 0, but got %v", tt.want, got)
+			}
+			if got := h.Utility(1); got != -tt.want {
+				t.Errorf("want is utility %v for player 1, but got %v", -tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeck(t *testing.T) {
+	t.Parallel()
+	d := Deck()
+	if len(d.Cards) != 6 || d.CardSet().Len() != 6 {
+		t.Fatalf("want is 6 distinct cards, but got %v", d.Cards)
+	}
+	d.Shuffle(rand.New(rand.NewSource(1)))
+	if d.CardSet() != Deck().CardSet() {
+		t.Errorf("shuffled deck has %v", d.Cards)
+	}
+}
@@ -0,0 +1,327 @@
+// Package river models a heads up river subgame of Texas Hold'em for the
+// cfr solvers.
+package river
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/cfr"
+)
+
+type ActionKind int
+
+const (
+	Check ActionKind = iota + 1
+	Bet
+	Call
+	Raise
+	Fold
+)
+
+func (k ActionKind) String() string {
+	switch k {
+	case Check:
+		return "check"
+	case Bet:
+		return "bet"
+	case Call:
+		return "call"
+	case Raise:
+		return "raise"
+	case Fold:
+		return "fold"
+	default:
+		return "unknown"
+	}
+}
+
+// Action is a move of a player. Amount is the size of a bet, or the total a
+// raise makes the bet.
+type Action struct {
+	Kind   ActionKind
+	Amount float64
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case Bet:
+		return "bet " + strconv.FormatFloat(a.Amount, 'f', -1, 64)
+	case Raise:
+		return "raise to " + strconv.FormatFloat(a.Amount, 'f', -1, 64)
+	default:
+		return a.Kind.String()
+	}
+}
+
+type Config struct {
+	Board poker.Board
+	// Ranges holds the holdings of both players. Player 0 acts first.
+	Ranges [2]*poker.Range
+	Pot    float64
+	// Stack is the effective stack behind.
+	Stack float64
+	// BetSizes holds the bets allowed as fractions of the pot.
+	BetSizes []float64
+	// RaiseSizes holds the raises allowed as fractions of the pot after
+	// calling.
+	RaiseSizes []float64
+	// AllIn allows betting and raising all in as well.
+	AllIn     bool
+	MaxRaises int
+}
+
+type Game struct {
+	cfg    Config
+	hands  [2][]poker.PersonalHand
+	names  [2][]string
+	values [2][]poker.HandValue
+	// deal0 holds the probability of each holding of player 0, and deal1
+	// that of each holding of player 1 given the one of player 0.
+	deal0 []float64
+	deal1 [][]float64
+}
+
+func NewGame(cfg Config) (*Game, error) {
+	if len(cfg.Board.Cards) != 5 {
+		return nil, fmt.Errorf("river: board must have 5 cards, got %d", len(cfg.Board.Cards))
+	}
+	board := cfg.Board.CardSet()
+	if board.Len() != 5 {
+		return nil, errors.New("river: duplicate cards on the board")
+	}
+	if cfg.Ranges[0] == nil || cfg.Ranges[1] == nil {
+		return nil, errors.New("river: missing range")
+	}
+	if cfg.Pot <= 0 || cfg.Stack < 0 {
+		return nil, errors.New("river: invalid pot or stack")
+	}
+
+	g := &Game{cfg: cfg}
+	var sets [2][]poker.CardSet
+	var weights [2][]float64
+	for p, r := range cfg.Ranges {
+		for _, c := range r.Exclude(board).Combos() {
+			cs := c.Hand.CardSet()
+			sets[p] = append(sets[p], cs)
+			weights[p] = append(weights[p], c.Weight)
+			g.hands[p] = append(g.hands[p], c.Hand)
+			g.names[p] = append(g.names[p], cs.String())
+			g.values[p] = append(g.values[p], cs.Union(board).Evaluate())
+		}
+	}
+
+	total := 0.0
+	g.deal0 = make([]float64, len(sets[0]))
+	g.deal1 = make([][]float64, len(sets[0]))
+	for i, a := range sets[0] {
+		g.deal1[i] = make([]float64, len(sets[1]))
+		sum := 0.0
+		for j, b := range sets[1] {
+			if !a.Overlaps(b) {
+				g.deal1[i][j] = weights[1][j]
+				sum += weights[1][j]
+			}
+		}
+		for j := range g.deal1[i] {
+			if sum > 0 {
+				g.deal1[i][j] /= sum
+			}
+		}
+		g.deal0[i] = weights[0][i] * sum
+		total += g.deal0[i]
+	}
+	if total == 0 {
+		return nil, errors.New("river: no holdings can be dealt")
+	}
+	for i := range g.deal0 {
+		g.deal0[i] /= total
+	}
+	return g, nil
+}
+
+func (g *Game) Root() cfr.State {
+	return &state{g: g, combo: [2]int{-1, -1}}
+}
+
+type state struct {
+	g       *Game
+	combo   [2]int
+	history string
+	bets    [2]float64
+	player  int
+	raises  int
+	// facing is set when the player to act faces a bet or raise.
+	facing   bool
+	done     bool
+	folder   int
+	showdown bool
+}
+
+func (s *state) Player() int {
+	if s.combo[0] < 0 || s.combo[1] < 0 {
+		return cfr.Chance
+	}
+	return s.player
+}
+
+func (s *state) IsTerminal() bool {
+	return s.done
+}
+
+func (s *state) Utility(player int) float64 {
+	var winner int
+	if s.showdown {
+		v0, v1 := s.g.values[0][s.combo[0]], s.g.values[1][s.combo[1]]
+		switch {
+		case v0 > v1:
+			winner = 0
+		case v1 > v0:
+			winner = 1
+		default:
+			return 0
+		}
+	} else {
+		winner = 1 - s.folder
+	}
+	won := s.g.cfg.Pot/2 + s.bets[1-winner]
+	if player == winner {
+		return won
+	}
+	return -won
+}
+
+func (s *state) InfoSet() string {
+	return s.g.names[s.player][s.combo[s.player]] + "|" + s.history
+}
+
+func (s *state) NumActions() int {
+	switch {
+	case s.combo[0] < 0:
+		return len(s.g.deal0)
+	case s.combo[1] < 0:
+		return len(s.g.deal1[s.combo[0]])
+	default:
+		return len(s.actions())
+	}
+}
+
+func (s *state) ChanceProbabilities() []float64 {
+	if s.combo[0] < 0 {
+		return s.g.deal0
+	}
+	return s.g.deal1[s.combo[0]]
+}
+
+func (s *state) actions() []Action {
+	cfg := s.g.cfg
+	pot := cfg.Pot + s.bets[0] + s.bets[1]
+	var sizes []float64
+	var kind ActionKind
+	var actions []Action
+	if !s.facing {
+		actions = []Action{{Kind: Check}}
+		kind, sizes = Bet, cfg.BetSizes
+	} else {
+		actions = []Action{{Kind: Fold}, {Kind: Call}}
+		if s.raises >= cfg.MaxRaises || s.bets[1-s.player] >= cfg.Stack {
+			return actions
+		}
+		kind, sizes = Raise, cfg.RaiseSizes
+		pot += s.bets[1-s.player] - s.bets[s.player]
+	}
+
+	var amounts []float64
+	for _, f := range sizes {
+		amounts = append(amounts, s.bets[1-s.player]+f*pot)
+	}
+	if cfg.AllIn {
+		amounts = append(amounts, cfg.Stack)
+	}
+	sort.Float64s(amounts)
+	last := s.bets[1-s.player]
+	for _, a := range amounts {
+		if a > cfg.Stack {
+			a = cfg.Stack
+		}
+		if a > last {
+			actions = append(actions, Action{Kind: kind, Amount: a})
+			last = a
+		}
+	}
+	return actions
+}
+
+func (s *state) Child(action int) cfr.State {
+	next := *s
+	switch {
+	case s.combo[0] < 0:
+		next.combo[0] = action
+		return &next
+	case s.combo[1] < 0:
+		next.combo[1] = action
+		return &next
+	}
+
+	next.history += string(rune('a' + action))
+	next.player = 1 - s.player
+	switch a := s.actions()[action]; a.Kind {
+	case Check:
+		next.done = s.player == 1
+		next.showdown = next.done
+	case Bet, Raise:
+		next.bets[s.player] = a.Amount
+		next.facing = true
+		if a.Kind == Raise {
+			next.raises++
+		}
+	case Call:
+		next.bets[s.player] = s.bets[1-s.player]
+		next.done, next.showdown = true, true
+	case Fold:
+		next.done, next.folder = true, s.player
+	}
+	return &next
+}
+
+// ComboStrategy is how often a holding takes each action of a spot.
+type ComboStrategy struct {
+	Hand          poker.PersonalHand
+	Probabilities []float64
+}
+
+// Spot is a decision of the subgame with the strategy of every holding of
+// the player to act.
+type Spot struct {
+	Player     int
+	Actions    []Action
+	Strategies []ComboStrategy
+}
+
+// Spot returns the decision reached by history, the indexes of the
+// actions taken so far, under st.
+func (g *Game) Spot(st cfr.Strategy, history ...int) (*Spot, error) {
+	s := &state{g: g}
+	for _, a := range history {
+		if s.done || a < 0 || a >= len(s.actions()) {
+			return nil, fmt.Errorf("river: invalid history %v", history)
+		}
+		s = s.Child(a).(*state)
+	}
+	if s.done {
+		return nil, fmt.Errorf("river: history %v ends the hand", history)
+	}
+
+	spot := &Spot{Player: s.player, Actions: s.actions()}
+	for i, h := range g.hands[s.player] {
+		key := g.names[s.player][i] + "|" + s.history
+		spot.Strategies = append(spot.Strategies, ComboStrategy{
+			Hand:          h,
+			Probabilities: st.Probabilities(key, len(spot.Actions)),
+		})
+	}
+	return spot, nil
+}
@@ -0,0 +1,175 @@
+package river
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/cfr"
+)
+
+func mustBoard(t *testing.T, s string) poker.Board {
+	t.Helper()
+	cards, err := poker.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return poker.Board{Cards: cards}
+}
+
+func mustRange(t *testing.T, s string) *poker.Range {
+	t.Helper()
+	r, err := poker.ParseRange(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestGame_Solve(t *testing.T) {
+	t.Parallel()
+	// Player 0 holds the nuts or air against a bluff catcher. With a pot
+	// sized bet it bluffs half its air and player 1 calls half the time.
+	g, err := NewGame(Config{
+		Board:    mustBoard(t, "Ks Kd 7h 4c 2s"),
+		Ranges:   [2]*poker.Range{mustRange(t, "AcAd, 6h5h"), mustRange(t, "QcQd")},
+		Pot:      1,
+		Stack:    1,
+		BetSizes: []float64{1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []cfr.Algorithm{cfr.Vanilla, cfr.Plus} {
+		a := a
+		t.Run(a.String(), func(t *testing.T) {
+			t.Parallel()
+			s := cfr.NewSolver(g, a, nil)
+			s.Run(3000)
+			st := s.AverageStrategy()
+			if e := cfr.Exploitability(g, st); e > 0.01 {
+				t.Errorf("want is exploitability about 0, but got %v", e)
+			}
+			if v := cfr.Value(g, st, 0); math.Abs(v-0.25) > 0.01 {
+				t.Errorf("want is value 0.25, but got %v", v)
+			}
+
+			open, err := g.Spot(st)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]float64{"[Ac Ad]": 1, "[5h 6h]": 0.5}
+			for _, cs := range open.Strategies {
+				name := cs.Hand.CardSet().String()
+				if p := cs.Probabilities[1]; math.Abs(p-want[name]) > 0.03 {
+					t.Errorf("want is %s betting %v, but got %v", name, want[name], p)
+				}
+			}
+			facing, err := g.Spot(st, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p := facing.Strategies[0].Probabilities[1]; math.Abs(p-0.5) > 0.03 {
+				t.Errorf("want is QQ calling 0.5, but got %v", p)
+			}
+		})
+	}
+}
+
+func TestGame_Spot(t *testing.T) {
+	t.Parallel()
+	g, err := NewGame(Config{
+		Board:      mustBoard(t, "Ks Kd 7h 4c 2s"),
+		Ranges:     [2]*poker.Range{mustRange(t, "AA"), mustRange(t, "QQ, JJ")},
+		Pot:        10,
+		Stack:      25,
+		BetSizes:   []float64{0.5, 1, 3},
+		RaiseSizes: []float64{1},
+		AllIn:      true,
+		MaxRaises:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		history     []int
+		wantPlayer  int
+		wantActions []Action
+		wantCombos  int
+	}{
+		{
+			wantPlayer:  0,
+			wantActions: []Action{{Kind: Check}, {Kind: Bet, Amount: 5}, {Kind: Bet, Amount: 10}, {Kind: Bet, Amount: 25}},
+			wantCombos:  6,
+		},
+		{
+			history:     []int{0},
+			wantPlayer:  1,
+			wantActions: []Action{{Kind: Check}, {Kind: Bet, Amount: 5}, {Kind: Bet, Amount: 10}, {Kind: Bet, Amount: 25}},
+			wantCombos:  12,
+		},
+		{
+			history:     []int{1},
+			wantPlayer:  1,
+			wantActions: []Action{{Kind: Fold}, {Kind: Call}, {Kind: Raise, Amount: 25}},
+			wantCombos:  12,
+		},
+		{
+			history:     []int{1, 2},
+			wantPlayer:  0,
+			wantActions: []Action{{Kind: Fold}, {Kind: Call}},
+			wantCombos:  6,
+		},
+		{
+			history:     []int{3},
+			wantPlayer:  1,
+			wantActions: []Action{{Kind: Fold}, {Kind: Call}},
+			wantCombos:  12,
+		},
+	}
+	for _, tt := range tests {
+		got, err := g.Spot(nil, tt.history...)
+		if err != nil {
+			t.Fatalf("Spot(%v) error: %v", tt.history, err)
+		}
+		if got.Player != tt.wantPlayer {
+			t.Errorf("history %v: want is player %d, but got %d", tt.history, tt.wantPlayer, got.Player)
+		}
+		if diff := cmp.Diff(got.Actions, tt.wantActions); diff != "" {
+			t.Errorf("want and got are different(-got +want): %s", diff)
+		}
+		if len(got.Strategies) != tt.wantCombos {
+			t.Errorf("history %v: want is %d combos, but got %d", tt.history, tt.wantCombos, len(got.Strategies))
+		}
+	}
+
+	for _, history := range [][]int{{0, 0}, {1, 0}, {4}, {-1}} {
+		if _, err := g.Spot(nil, history...); err == nil {
+			t.Errorf("Spot(%v): want error but got nil", history)
+		}
+	}
+}
+
+func TestNewGame_Error(t *testing.T) {
+	t.Parallel()
+	board := mustBoard(t, "Ks Kd 7h 4c 2s")
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "turn", cfg: Config{Board: mustBoard(t, "Ks Kd 7h 4c"), Ranges: [2]*poker.Range{mustRange(t, "AA"), mustRange(t, "QQ")}, Pot: 1}},
+		{name: "missing range", cfg: Config{Board: board, Ranges: [2]*poker.Range{mustRange(t, "AA")}, Pot: 1}},
+		{name: "no pot", cfg: Config{Board: board, Ranges: [2]*poker.Range{mustRange(t, "AA"), mustRange(t, "QQ")}}},
+		{name: "blocked", cfg: Config{Board: board, Ranges: [2]*poker.Range{mustRange(t, "AcAd"), mustRange(t, "AcAh")}, Pot: 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := NewGame(tt.cfg); err == nil {
+				t.Error("want error but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yuzuy/poker"
+)
+
+type equityResult struct {
+	Range  string  `json:"range"`
+	Equity float64 `json:"equity"`
+	Win    float64 `json:"win"`
+	Tie    float64 `json:"tie"`
+}
+
+type equityOutput struct {
+	Board []poker.Card `json:"board"`
+	// Iterations is zero when every runout was dealt.
+	Iterations int            `json:"iterations"`
+	Results    []equityResult `json:"results"`
+}
+
+func equity(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("equity", flag.ContinueOnError)
+	fs.SetOutput(out)
+	board := fs.String("board", "", "board cards")
+	iterations := fs.Int("iterations", 100000, "Monte Carlo iterations")
+	exact := fs.Bool("exact", false, "deal every runout instead of sampling")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the samples")
+	workers := fs.Int("workers", runtime.NumCPU(), "goroutines sampling in parallel")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New(`equity needs at least 2 hands or ranges such as "AsKs" "QQ+"`)
+	}
+	if *workers <= 0 {
+		return errors.New("workers must be positive")
+	}
+	boardCards, err := poker.ParseCards(*board)
+	if err != nil {
+		return err
+	}
+	ranges := make([]*poker.Range, fs.NArg())
+	for i, arg := range fs.Args() {
+		if ranges[i], err = poker.ParseRange(arg); err != nil {
+			return err
+		}
+	}
+
+	res := equityOutput{Board: boardCards, Results: make([]equityResult, len(ranges))}
+	var results []poker.EquityResult
+	if *exact {
+		results, err = poker.EnumerateEquity(ranges, poker.Board{Cards: boardCards})
+	} else {
+		res.Iterations = *iterations
+		results, err = sampleEquity(ranges, poker.Board{Cards: boardCards}, *iterations, *seed, *workers)
+	}
+	if err != nil {
+		return err
+	}
+	for i, r := range results {
+		res.Results[i] = equityResult{Range: fs.Arg(i), Equity: r.Equity, Win: r.Win, Tie: r.Tie}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	if len(boardCards) > 0 {
+		fmt.Fprintf(out, "Board %s\n", painter{}.cards(boardCards))
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "range\tequity\twin\ttie\t")
+	for _, r := range res.Results {
+		fmt.Fprintf(w, "%s\t%.2f%%\t%.2f%%\t%.2f%%\t\n", r.Range, 100*r.Equity, 100*r.Win, 100*r.Tie)
+	}
+	return w.Flush()
+}
+
+// sampleEquity splits the iterations between workers sampling with their
+// own seeds and averages what they find.
+func sampleEquity(ranges []*poker.Range, board poker.Board, iterations int, seed int64, workers int) ([]poker.EquityResult, error) {
+	if iterations <= 0 {
+		return nil, errors.New("iterations must be positive")
+	}
+	if workers > iterations {
+		workers = iterations
+	}
+	results := make([][]poker.EquityResult, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		n := iterations / workers
+		if i < iterations%workers {
+			n++
+		}
+		wg.Add(1)
+		go func(i, n int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed + int64(i)))
+			results[i], errs[i] = poker.MonteCarloEquity(ranges, board, n, rnd)
+			for j := range results[i] {
+				share := float64(n) / float64(iterations)
+				results[i][j].Equity *= share
+				results[i][j].Win *= share
+				results[i][j].Tie *= share
+			}
+		}(i, n)
+	}
+	wg.Wait()
+
+	total := make([]poker.EquityResult, len(ranges))
+	for i, rs := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for j, r := range rs {
+			total[j].Equity += r.Equity
+			total[j].Win += r.Win
+			total[j].Tie += r.Tie
+		}
+	}
+	return total, nil
+}
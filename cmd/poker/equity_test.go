@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/yuzuy/poker"
+)
+
+type equityJSON struct {
+	Board      []string
+	Iterations int
+	Results    []struct {
+		Range            string
+		Equity, Win, Tie float64
+	}
+}
+
+func TestRun_Equity(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    []float64
+		delta   float64
+		wantErr bool
+	}{
+		{
+			name:  "aces against kings",
+			args:  []string{"equity", "-json", "-seed", "1", "-iterations", "20000", "-workers", "4", "AsAh", "KdKc"},
+			want:  []float64{0.82, 0.18},
+			delta: 0.02,
+		},
+		{
+			name:  "exact on the turn",
+			args:  []string{"equity", "-json", "-exact", "-board", "2c 7d 9h Ts", "AsAh", "KdKc"},
+			want:  []float64{42.0 / 44, 2.0 / 44},
+			delta: 1e-9,
+		},
+		{
+			name:  "range",
+			args:  []string{"equity", "-json", "-exact", "-board", "Ac 7d 2h 3s 8c", "KK", "AKs"},
+			want:  []float64{0, 1},
+			delta: 1e-9,
+		},
+		{name: "one hand", args: []string{"equity", "AsAh"}, wantErr: true},
+		{name: "invalid range", args: []string{"equity", "AsAh", "Xx"}, wantErr: true},
+		{name: "invalid board", args: []string{"equity", "-board", "Zz", "AsAh", "KdKc"}, wantErr: true},
+		{name: "no workers", args: []string{"equity", "-workers", "0", "AsAh", "KdKc"}, wantErr: true},
+		{name: "no iterations", args: []string{"equity", "-iterations", "0", "AsAh", "KdKc"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := run(tt.args, nil, &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want is error %v, but got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got equityJSON
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Results) != len(tt.want) {
+				t.Fatalf("want is %d results, but got %d", len(tt.want), len(got.Results))
+			}
+			for i, r := range got.Results {
+				if math.Abs(r.Equity-tt.want[i]) > tt.delta {
+					t.Errorf("%s: want is equity %v, but got %v", r.Range, tt.want[i], r.Equity)
+				}
+			}
+		})
+	}
+}
+
+func TestSampleEquity(t *testing.T) {
+	t.Parallel()
+	aces, _ := poker.ParseRange("AsAh")
+	kings, _ := poker.ParseRange("KdKc")
+	ranges := []*poker.Range{aces, kings}
+	got, err := sampleEquity(ranges, poker.Board{}, 1000, 7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := sampleEquity(ranges, poker.Board{}, 1000, 7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != again[0] {
+		t.Errorf("same seed gave %+v and %+v", got[0], again[0])
+	}
+	if sum := got[0].Equity + got[1].Equity; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("want is equities summing to 1, but got %v", sum)
+	}
+
+	// A single worker samples like MonteCarloEquity with the seed.
+	one, err := sampleEquity(ranges, poker.Board{}, 1000, 7, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := poker.MonteCarloEquity(ranges, poker.Board{}, 1000, rand.New(rand.NewSource(7)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(one[0].Equity-want[0].Equity) > 1e-9 {
+		t.Errorf("want is %v with one worker, but got %v", want[0].Equity, one[0].Equity)
+	}
+}
+
+func TestRun_EquityText(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	if err := run([]string{"equity", "-exact", "-board", "2c 7d 9h Ts 3s", "AsAh", "KdKc"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "Board 2♣ 7♦ 9♥ T♠ 3♠\n" +
+		"  range   equity      win    tie\n" +
+		"   AsAh  100.00%  100.00%  0.00%\n" +
+		"   KdKc    0.00%    0.00%  0.00%\n"
+	if out.String() != want {
+		t.Errorf("want is\n%s\nbut got\n%s", want, out.String())
+	}
+}
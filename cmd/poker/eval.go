@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yuzuy/poker"
+)
+
+type evaluation struct {
+	Cards []poker.Card   `json:"cards"`
+	Best  *poker.Hand    `json:"best"`
+	Rank  poker.HandRank `json:"rank"`
+}
+
+type evalOutput struct {
+	Hands []evaluation `json:"hands"`
+	// Winners holds the indexes of the strongest hands when comparing.
+	Winners []int `json:"winners,omitempty"`
+}
+
+func eval(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fs.SetOutput(out)
+	board := fs.String("board", "", "board cards added to every hand")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New(`eval needs a hand such as "As Ks Qs Js Ts"`)
+	}
+	boardCards, err := poker.ParseCards(*board)
+	if err != nil {
+		return err
+	}
+
+	var res evalOutput
+	for _, arg := range fs.Args() {
+		cards, err := poker.ParseCards(arg)
+		if err != nil {
+			return err
+		}
+		cards = append(cards, boardCards...)
+		if poker.NewCardSet(cards...).Len() != len(cards) {
+			return fmt.Errorf("duplicated card in %q", arg)
+		}
+		best, err := poker.BestHand(cards)
+		if err != nil {
+			return err
+		}
+		res.Hands = append(res.Hands, evaluation{Cards: cards, Best: best, Rank: best.Rank()})
+	}
+	if len(res.Hands) > 1 {
+		for i, h := range res.Hands {
+			if len(res.Winners) == 0 {
+				res.Winners = []int{i}
+				continue
+			}
+			switch h.Best.Compare(res.Hands[res.Winners[0]].Best) {
+			case poker.Win:
+				res.Winners = []int{i}
+			case poker.Draw:
+				res.Winners = append(res.Winners, i)
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	p := painter{}
+	for i, h := range res.Hands {
+		fmt.Fprintf(out, "%d. %s: %s (%s)\n", i+1, p.cards(h.Cards), h.Rank, p.cards(h.Best.Cards))
+	}
+	switch {
+	case len(res.Winners) == 1:
+		fmt.Fprintf(out, "Hand %d wins\n", res.Winners[0]+1)
+	case len(res.Winners) > 1:
+		tied := make([]string, len(res.Winners))
+		for i, w := range res.Winners {
+			tied[i] = fmt.Sprint(w + 1)
+		}
+		fmt.Fprintf(out, "Hands %s split the pot\n", strings.Join(tied, " and "))
+	}
+	return nil
+}
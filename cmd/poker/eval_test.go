@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun_Eval(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "royal flush",
+			args: []string{"eval", "As Ks Qs Js Ts"},
+			want: "1. A♠ K♠ Q♠ J♠ T♠: royal flush (A♠ K♠ Q♠ J♠ T♠)\n",
+		},
+		{
+			name: "compare",
+			args: []string{"eval", "-board", "2c 7d 9h Th 3s", "As Ah", "Kd Kc"},
+			want: "1. A♠ A♥ 2♣ 7♦ 9♥ T♥ 3♠: one pair (A♠ A♥ T♥ 9♥ 7♦)\n" +
+				"2. K♦ K♣ 2♣ 7♦ 9♥ T♥ 3♠: one pair (K♦ K♣ T♥ 9♥ 7♦)\n" +
+				"Hand 1 wins\n",
+		},
+		{
+			name: "split",
+			args: []string{"eval", "-board", "2c 7d 9h Th 3s", "As Ah", "Kd Kc", "Ad Ac"},
+			want: "1. A♠ A♥ 2♣ 7♦ 9♥ T♥ 3♠: one pair (A♠ A♥ T♥ 9♥ 7♦)\n" +
+				"2. K♦ K♣ 2♣ 7♦ 9♥ T♥ 3♠: one pair (K♦ K♣ T♥ 9♥ 7♦)\n" +
+				"3. A♦ A♣ 2♣ 7♦ 9♥ T♥ 3♠: one pair (A♦ A♣ T♥ 9♥ 7♦)\n" +
+				"Hands 1 and 3 split the pot\n",
+		},
+		{name: "no hand", args: []string{"eval"}, wantErr: true},
+		{name: "too few cards", args: []string{"eval", "As Ks"}, wantErr: true},
+		{name: "invalid card", args: []string{"eval", "As Ks Qs Js Xx"}, wantErr: true},
+		{name: "duplicated card", args: []string{"eval", "-board", "As 2c 3d", "As Ks"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := run(tt.args, nil, &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want is error %v, but got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(out.String(), tt.want); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRun_EvalJSON(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	if err := run([]string{"eval", "-json", "As Ks Qs Js Ts 2c 2d", "2h 2s 3c 4d 5h 7s 8s"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Hands []struct {
+			Cards []string
+			Best  string
+			Rank  string
+		}
+		Winners []int
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Hands) != 2 {
+		t.Fatalf("want is 2 hands, but got %d", len(got.Hands))
+	}
+	if got.Hands[0].Best != "As Ks Qs Js Ts" || got.Hands[0].Rank != "royal flush" || len(got.Hands[0].Cards) != 7 {
+		t.Errorf("want is a royal flush of 7 cards first, but got %+v", got.Hands[0])
+	}
+	if diff := cmp.Diff(got.Winners, []int{0}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
@@ -0,0 +1,40 @@
+// Command poker plays Hold'em against bots in the terminal, and evaluates
+// hands and equities.
+//
+// Usage:
+//
+//	poker [play] [flags]
+//	poker eval [-board cards] [-json] hand...
+//	poker equity [-board cards] [-iterations n] [-exact] [-seed n] [-workers n] [-json] range...
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "poker:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, in io.Reader, out io.Writer) error {
+	cmd := "play"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+	switch cmd {
+	case "play":
+		return play(args, in, out)
+	case "eval":
+		return eval(args, out)
+	case "equity":
+		return equity(args, out)
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+	"github.com/yuzuy/poker/holdem/bot"
+)
+
+func play(args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("play", flag.ContinueOnError)
+	fs.SetOutput(out)
+	bots := fs.Int("bots", 3, "number of computer opponents, 1 to 8")
+	stack := fs.Int("stack", 200, "starting stack")
+	sb := fs.Int("sb", 1, "small blind")
+	bb := fs.Int("bb", 2, "big blind")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the shuffles and bots")
+	color := fs.Bool("color", os.Getenv("NO_COLOR") == "", "color the cards")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bots < 1 || *bots > 8 {
+		return errors.New("bots must be between 1 and 8")
+	}
+	cfg := holdem.Config{SmallBlind: *sb, BigBlind: *bb}
+	rnd := rand.New(rand.NewSource(*seed))
+	p := painter{color: *color}
+	scanner := bufio.NewScanner(in)
+
+	names := []string{"You"}
+	you := &human{in: scanner, out: out, painter: p, names: names}
+	agents := []holdem.Agent{you}
+	kinds := []struct {
+		name  string
+		agent func() holdem.Agent
+	}{
+		{"Tag", func() holdem.Agent { return bot.NewTightAggressive() }},
+		{"Shark", func() holdem.Agent { return bot.NewEquity(300, rnd) }},
+		{"Station", func() holdem.Agent { return bot.CallingStation{} }},
+		{"Maniac", func() holdem.Agent { return bot.NewRandom(rnd) }},
+	}
+	for i := 0; i < *bots; i++ {
+		k := kinds[i%len(kinds)]
+		name := k.name
+		if i >= len(kinds) {
+			name += strconv.Itoa(i/len(kinds) + 1)
+		}
+		names = append(names, name)
+		agents = append(agents, k.agent())
+	}
+	you.names = names
+
+	stacks := make([]int, len(agents))
+	for i := range stacks {
+		stacks[i] = *stack
+	}
+	button := rnd.Intn(len(stacks))
+	for hand := 1; ; hand++ {
+		g, err := holdem.NewGame(cfg, stacks, button, poker.NewSeededDeck(rnd.Int63()),
+			&narrator{out: out, painter: p, names: names, hand: hand})
+		if err != nil {
+			return err
+		}
+		stacks = g.Play(agents).Stacks
+		if you.quit {
+			fmt.Fprintln(out, "Bye.")
+			return nil
+		}
+
+		left := 0
+		for _, s := range stacks[1:] {
+			if s > 0 {
+				left++
+			}
+		}
+		switch {
+		case stacks[0] == 0:
+			fmt.Fprintln(out, p.bold("You are out of chips."))
+			return nil
+		case left == 0:
+			fmt.Fprintln(out, p.bold("You won every chip at the table!"))
+			return nil
+		}
+		fmt.Fprint(out, "Press Enter for the next hand, or q to quit: ")
+		if !scanner.Scan() || strings.TrimSpace(scanner.Text()) == "q" {
+			fmt.Fprintln(out, "Bye.")
+			return nil
+		}
+		for button = (button + 1) % len(stacks); stacks[button] == 0; {
+			button = (button + 1) % len(stacks)
+		}
+	}
+}
+
+// narrator tells the story of a hand from the seat of the human.
+type narrator struct {
+	out     io.Writer
+	painter painter
+	names   []string
+	hand    int
+	board   []poker.Card
+	hands   []*poker.Hand
+}
+
+// says returns the name of seat with verb, which agrees with "You" for the
+// human.
+func (n *narrator) says(seat int, verb string) string {
+	if seat != 0 {
+		return n.names[seat] + " " + verb
+	}
+	if verb == "has" {
+		return "You have"
+	}
+	first, rest := verb, ""
+	if i := strings.IndexByte(verb, ' '); i >= 0 {
+		first, rest = verb[:i], verb[i:]
+	}
+	return "You " + strings.TrimSuffix(first, "s") + rest
+}
+
+func (n *narrator) Observe(e holdem.Event) {
+	p := n.painter
+	switch e := e.(type) {
+	case holdem.HandStarted:
+		fmt.Fprintf(n.out, "\n%s\n", p.bold(fmt.Sprintf("=== Hand %d === %s the button", n.hand, n.says(e.Button, "has"))))
+		var stacks []string
+		for i, s := range e.Stacks {
+			if s > 0 {
+				stacks = append(stacks, fmt.Sprintf("%s %d", n.names[i], s))
+			}
+		}
+		fmt.Fprintf(n.out, "Stacks: %s\n", strings.Join(stacks, ", "))
+	case holdem.BlindsPosted:
+		for i, a := range e.Antes {
+			if a > 0 {
+				fmt.Fprintf(n.out, "%s an ante of %d\n", n.says(i, "posts"), a)
+			}
+		}
+		fmt.Fprintf(n.out, "%s the small blind of %d\n", n.says(e.SmallBlindSeat, "posts"), e.SmallBlind)
+		fmt.Fprintf(n.out, "%s the big blind of %d\n", n.says(e.BigBlindSeat, "posts"), e.BigBlind)
+	case holdem.CardsDealt:
+		if e.Seat == 0 {
+			fmt.Fprintf(n.out, "Your cards: %s\n", p.cards(e.Hole.Cards))
+		}
+	case holdem.ActionTaken:
+		fmt.Fprintf(n.out, "%s\n", n.says(e.Seat, describe(e.Action)))
+	case holdem.StreetDealt:
+		n.board = append(n.board, e.Cards...)
+		fmt.Fprintf(n.out, "%s\n", p.bold(fmt.Sprintf("--- %s: %s", capitalize(e.Street.String()), p.cards(n.board))))
+	case holdem.BetReturned:
+		to := n.names[e.Seat]
+		if e.Seat == 0 {
+			to = "you"
+		}
+		fmt.Fprintf(n.out, "%d uncalled returned to %s\n", e.Amount, to)
+	case holdem.Showdown:
+		n.hands = e.Hands
+		for i, h := range e.Hands {
+			if h != nil {
+				fmt.Fprintf(n.out, "%s %s: %s (%s)\n", n.says(i, "shows"), p.cards(e.Holes[i].Cards), h.Rank(), p.cards(h.Cards))
+			}
+		}
+	case holdem.PotAwarded:
+		pot := "the pot"
+		if e.Index > 0 {
+			pot = fmt.Sprintf("side pot %d", e.Index)
+		}
+		for _, w := range e.Pot.Winners {
+			line := fmt.Sprintf("%s %d from %s", n.says(w, "wins"), e.Won[w], pot)
+			if n.hands != nil {
+				line += fmt.Sprintf(" with %s", n.hands[w].Rank())
+			}
+			fmt.Fprintf(n.out, "%s\n", p.bold(line))
+		}
+	}
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func describe(a holdem.Action) string {
+	switch a.Kind {
+	case holdem.Fold:
+		return "folds"
+	case holdem.Check:
+		return "checks"
+	case holdem.Call:
+		return "calls"
+	case holdem.Bet:
+		return fmt.Sprintf("bets %d", a.Amount)
+	default:
+		return fmt.Sprintf("raises to %d", a.Amount)
+	}
+}
+
+// human asks the player at the terminal for its actions.
+type human struct {
+	in      *bufio.Scanner
+	out     io.Writer
+	painter painter
+	names   []string
+	quit    bool
+}
+
+var errQuit = errors.New("quit")
+
+func (h *human) Act(v *holdem.View) holdem.Action {
+	if h.quit {
+		return v.CheckOrFold()
+	}
+	h.show(v)
+	for {
+		fmt.Fprint(h.out, "> ")
+		if !h.in.Scan() {
+			h.quit = true
+			return v.CheckOrFold()
+		}
+		a, err := parseAction(h.in.Text(), v)
+		if err == errQuit {
+			h.quit = true
+			return v.CheckOrFold()
+		}
+		if err != nil {
+			fmt.Fprintln(h.out, err)
+			continue
+		}
+		return a
+	}
+}
+
+func (h *human) show(v *holdem.View) {
+	p := h.painter
+	var players []string
+	for i, s := range v.Stacks {
+		switch {
+		case v.Folded[i]:
+			continue
+		case v.Bets[i] > 0:
+			players = append(players, fmt.Sprintf("%s %d (bet %d)", h.names[i], s, v.Bets[i]))
+		default:
+			players = append(players, fmt.Sprintf("%s %d", h.names[i], s))
+		}
+	}
+	fmt.Fprintf(h.out, "Pot %d | %s\n", v.Pot, strings.Join(players, ", "))
+	board := "-"
+	if len(v.Board.Cards) > 0 {
+		board = p.cards(v.Board.Cards)
+	}
+	fmt.Fprintf(h.out, "Board %s | You hold %s\n", board, p.cards(v.Hole.Cards))
+	fmt.Fprintf(h.out, "%s\n", p.bold(strings.Join(legalActions(v), " | ")))
+}
+
+// legalActions lists what the player may do, with the commands to type.
+func legalActions(v *holdem.View) []string {
+	var actions []string
+	if v.ToCall > 0 {
+		actions = append(actions, "[f]old", fmt.Sprintf("[c]all %d", v.ToCall))
+	} else {
+		actions = append(actions, "[c]heck")
+	}
+	if low, high, ok := raiseRange(v); ok {
+		verb := "[b]et"
+		if v.Bets[v.Seat]+v.ToCall > 0 {
+			verb = "[r]aise to"
+		}
+		if low == high {
+			actions = append(actions, fmt.Sprintf("%s %d", verb, high))
+		} else {
+			actions = append(actions, fmt.Sprintf("%s %d-%d", verb, low, high))
+		}
+		actions = append(actions, "[a]ll in")
+	}
+	return append(actions, "[q]uit")
+}
+
+// raiseRange returns the smallest and largest totals the player may bet or
+// raise to.
+func raiseRange(v *holdem.View) (int, int, bool) {
+	high := v.MaxBet()
+	if v.MinRaise == 0 || high <= v.Bets[v.Seat]+v.ToCall {
+		return 0, 0, false
+	}
+	if v.MinRaise < high {
+		return v.MinRaise, high, true
+	}
+	return high, high, true
+}
+
+func parseAction(text string, v *holdem.View) (holdem.Action, error) {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) == 0 {
+		return holdem.Action{}, errors.New("type an action")
+	}
+	switch fields[0] {
+	case "q", "quit":
+		return holdem.Action{}, errQuit
+	case "f", "fold":
+		if v.ToCall == 0 {
+			return holdem.Action{}, errors.New("nothing to fold to, check instead")
+		}
+		return holdem.Action{Kind: holdem.Fold}, nil
+	case "c", "k", "check", "call":
+		return v.CheckOrCall(), nil
+	case "a", "all", "allin":
+		if _, high, ok := raiseRange(v); ok {
+			return v.BetTo(high), nil
+		}
+		return v.CheckOrCall(), nil
+	case "b", "r", "bet", "raise":
+		low, high, ok := raiseRange(v)
+		if !ok {
+			return holdem.Action{}, errors.New("you cannot raise")
+		}
+		if len(fields) < 2 {
+			return holdem.Action{}, fmt.Errorf("say how much, from %d to %d", low, high)
+		}
+		amount, err := strconv.Atoi(fields[1])
+		if err != nil || amount < low || amount > high {
+			return holdem.Action{}, fmt.Errorf("the amount must be from %d to %d", low, high)
+		}
+		return v.BetTo(amount), nil
+	default:
+		return holdem.Action{}, fmt.Errorf("unknown action %q", fields[0])
+	}
+}
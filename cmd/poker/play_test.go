@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker/holdem"
+)
+
+func TestParseAction(t *testing.T) {
+	t.Parallel()
+	facingBet := &holdem.View{Seat: 0, Stacks: []int{90, 80}, Bets: []int{10, 20}, ToCall: 10, MinRaise: 30}
+	unopened := &holdem.View{Seat: 0, Stacks: []int{100, 100}, Bets: []int{0, 0}, MinRaise: 2}
+	tests := []struct {
+		name    string
+		v       *holdem.View
+		text    string
+		want    holdem.Action
+		wantErr bool
+	}{
+		{name: "call", v: facingBet, text: "c", want: holdem.Action{Kind: holdem.Call}},
+		{name: "check", v: unopened, text: "check", want: holdem.Action{Kind: holdem.Check}},
+		{name: "fold", v: facingBet, text: "F", want: holdem.Action{Kind: holdem.Fold}},
+		{name: "fold for free", v: unopened, text: "f", wantErr: true},
+		{name: "bet", v: unopened, text: "b 10", want: holdem.Action{Kind: holdem.Bet, Amount: 10}},
+		{name: "raise", v: facingBet, text: "raise 45", want: holdem.Action{Kind: holdem.Raise, Amount: 45}},
+		{name: "below the minimum", v: facingBet, text: "r 25", wantErr: true},
+		{name: "above the stack", v: facingBet, text: "r 101", wantErr: true},
+		{name: "no amount", v: facingBet, text: "r", wantErr: true},
+		{name: "all in", v: facingBet, text: "a", want: holdem.Action{Kind: holdem.Raise, Amount: 100}},
+		{name: "empty", v: facingBet, text: " ", wantErr: true},
+		{name: "unknown", v: facingBet, text: "shove", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseAction(tt.text, tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want is error %v, but got %v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+	if _, err := parseAction("q", facingBet); err != errQuit {
+		t.Errorf("want is %v, but got %v", errQuit, err)
+	}
+}
+
+func TestLegalActions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		v    *holdem.View
+		want []string
+	}{
+		{
+			name: "unopened",
+			v:    &holdem.View{Seat: 0, Stacks: []int{100, 100}, Bets: []int{0, 0}, MinRaise: 2},
+			want: []string{"[c]heck", "[b]et 2-100", "[a]ll in", "[q]uit"},
+		},
+		{
+			name: "short stack",
+			v:    &holdem.View{Seat: 0, Stacks: []int{15, 80}, Bets: []int{10, 20}, ToCall: 10, MinRaise: 30},
+			want: []string{"[f]old", "[c]all 10", "[r]aise to 25", "[a]ll in", "[q]uit"},
+		},
+		{
+			name: "cannot raise",
+			v:    &holdem.View{Seat: 0, Stacks: []int{5, 80}, Bets: []int{10, 20}, ToCall: 5},
+			want: []string{"[f]old", "[c]all 5", "[q]uit"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(legalActions(tt.v), tt.want); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRun_Play(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		want  []string
+	}{
+		{
+			name:  "quit",
+			args:  []string{"-seed", "1", "-color=false"},
+			input: "q\n",
+			want:  []string{"=== Hand 1 ===", "Your cards:", "Bye."},
+		},
+		{
+			name:  "call down",
+			args:  []string{"play", "-bots", "2", "-seed", "1", "-color=false"},
+			input: strings.Repeat("c\n", 4) + "c\n\n",
+			want:  []string{"Tag shows Q♣ 7♥: flush", "Tag wins 8 from the pot with flush", "=== Hand 2 ==="},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			if err := run(tt.args, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("output has no %q:\n%s", w, out.String())
+				}
+			}
+		})
+	}
+	for _, args := range [][]string{{"-bots", "0"}, {"deal"}} {
+		if err := run(args, strings.NewReader(""), &bytes.Buffer{}); err == nil {
+			t.Errorf("run(%q): want error but got nil", args)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/yuzuy/poker"
+)
+
+const (
+	red   = "\x1b[31m"
+	bold  = "\x1b[1m"
+	reset = "\x1b[0m"
+)
+
+var suitSymbols = map[poker.Suit]string{
+	poker.Spade:   "♠",
+	poker.Heart:   "♥",
+	poker.Diamond: "♦",
+	poker.Club:    "♣",
+}
+
+// painter renders cards and text, with ANSI colors when color is set.
+type painter struct {
+	color bool
+}
+
+// card renders c as its text form with the suit as a symbol, or "??" when
+// c is not a card.
+func (p painter) card(c poker.Card) string {
+	text, err := c.MarshalText()
+	if err != nil {
+		return "??"
+	}
+	s := string(text[:1]) + suitSymbols[c.Suit]
+	if p.color && (c.Suit == poker.Heart || c.Suit == poker.Diamond) {
+		return red + s + reset
+	}
+	return s
+}
+
+func (p painter) cards(cards []poker.Card) string {
+	s := make([]string, len(cards))
+	for i, c := range cards {
+		s[i] = p.card(c)
+	}
+	return strings.Join(s, " ")
+}
+
+func (p painter) bold(s string) string {
+	if p.color {
+		return bold + s + reset
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/yuzuy/poker"
+)
+
+func TestPainter_cards(t *testing.T) {
+	t.Parallel()
+	cards, err := poker.ParseCards("As Td 2c Kh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		color bool
+		want  string
+	}{
+		{
+			name: "plain",
+			want: "A♠ T♦ 2♣ K♥",
+		},
+		{
+			name:  "red suits",
+			color: true,
+			want:  "A♠ \x1b[31mT♦\x1b[0m 2♣ \x1b[31mK♥\x1b[0m",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := (painter{color: tt.color}).cards(cards); got != tt.want {
+				t.Errorf("want is %q, but got %q", tt.want, got)
+			}
+		})
+	}
+	if got := (painter{}).card(poker.Card{}); got != "??" {
+		t.Errorf("want is %q for no card, but got %q", "??", got)
+	}
+}
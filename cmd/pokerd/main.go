@@ -0,0 +1,44 @@
+// Command pokerd hosts Hold'em tables over WebSocket.
+//
+// Clients connect to ws://ADDR/?table=NAME and exchange the JSON messages
+// of package github.com/yuzuy/poker/holdem/server.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yuzuy/poker/holdem"
+	"github.com/yuzuy/poker/holdem/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	seats := flag.Int("seats", 6, "seats per table")
+	stack := flag.Int("stack", 200, "chips a player sits down with")
+	sb := flag.Int("sb", 1, "small blind")
+	bb := flag.Int("bb", 2, "big blind")
+	ante := flag.Int("ante", 0, "ante")
+	actionTimeout := flag.Duration("action-timeout", 30*time.Second, "time to act before checking or folding")
+	reconnectTimeout := flag.Duration("reconnect-timeout", time.Minute, "time a disconnected player keeps its seat")
+	handDelay := flag.Duration("hand-delay", 2*time.Second, "pause between hands")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the shuffles")
+	flag.Parse()
+
+	srv, err := server.New(server.Config{
+		Game:             holdem.Config{SmallBlind: *sb, BigBlind: *bb, Ante: *ante},
+		Seats:            *seats,
+		Stack:            *stack,
+		ActionTimeout:    *actionTimeout,
+		ReconnectTimeout: *reconnectTimeout,
+		HandDelay:        *handDelay,
+		Seed:             *seed,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("pokerd listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
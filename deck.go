@@ -13,7 +13,6 @@ func (d *Deck) Draw() Card {
 }
 
 func (d *Deck) Reset() {
-	suits := []Suit{Spade, Club, Diamond, Heart}
 	ranks := []CardRank{Ace, Deuce, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King}
 	d.Cards = make([]Card, 0, 52)
 	for _, s := range suits {
@@ -26,3 +25,23 @@ func (d *Deck) Reset() {
 		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
 	})
 }
+
+// NewDeck returns a deck of the given cards, for games not played with the
+// 52 cards of Reset.
+func NewDeck(cards ...Card) *Deck {
+	return &Deck{Cards: append([]Card(nil), cards...)}
+}
+
+func (d *Deck) Shuffle(r *rand.Rand) {
+	r.Shuffle(len(d.Cards), func(i, j int) {
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	})
+}
+
+// NewSeededDeck returns the 52 cards shuffled in an order set by seed, so
+// that a deal can be reproduced from its seed.
+func NewSeededDeck(seed int64) *Deck {
+	d := FullCardSet.Deck()
+	d.Shuffle(rand.New(rand.NewSource(seed)))
+	return d
+}
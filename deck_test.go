@@ -1,6 +1,7 @@
 package poker
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -99,3 +100,51 @@ func TestDeck_Reset_HaveAllCards(t *testing.T) {
 		checkList[v.Suit][v.Rank] = true
 	}
 }
+
+func TestNewDeck(t *testing.T) {
+	t.Parallel()
+
+	cards := []Card{{Suit: Spade, Rank: Jack}, {Suit: Spade, Rank: Queen}, {Suit: Spade, Rank: King}}
+	d := NewDeck(cards...)
+	if diff := cmp.Diff(d.Cards, cards); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	d.Cards[0] = Card{Suit: Heart, Rank: Ace}
+	if cards[0].Rank != Jack {
+		t.Errorf("NewDeck shares the given cards")
+	}
+}
+
+func TestDeck_Shuffle(t *testing.T) {
+	t.Parallel()
+
+	cards := FullCardSet.Cards()
+	a := NewDeck(cards...)
+	a.Shuffle(rand.New(rand.NewSource(1)))
+	b := NewDeck(cards...)
+	b.Shuffle(rand.New(rand.NewSource(1)))
+	if diff := cmp.Diff(a.Cards, b.Cards); diff != "" {
+		t.Errorf("same seed shuffled differently(-got +want): %s", diff)
+	}
+	if cmp.Equal(a.Cards, cards) {
+		t.Errorf("deck was not shuffled")
+	}
+	if a.CardSet() != FullCardSet {
+		t.Errorf("want is all cards shuffled, but got %v", a.CardSet())
+	}
+}
+
+func TestNewSeededDeck(t *testing.T) {
+	t.Parallel()
+
+	a, b := NewSeededDeck(42), NewSeededDeck(42)
+	if diff := cmp.Diff(a.Cards, b.Cards); diff != "" {
+		t.Errorf("same seed dealt differently(-got +want): %s", diff)
+	}
+	if cmp.Equal(a.Cards, NewSeededDeck(43).Cards) {
+		t.Errorf("different seeds dealt the same deck")
+	}
+	if a.CardSet() != FullCardSet {
+		t.Errorf("want is all cards dealt, but got %v", a.CardSet())
+	}
+}
@@ -0,0 +1,201 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+type EquityResult struct {
+	// Equity is the share of the pot won, with split pots divided among the
+	// tied players.
+	Equity float64
+	Win    float64
+	Tie    float64
+}
+
+type weightedCombo struct {
+	cards  CardSet
+	weight float64
+}
+
+type equityCalc struct {
+	board   CardSet
+	ranges  [][]weightedCombo
+	results []EquityResult
+	total   float64
+	values  []HandValue
+}
+
+func newEquityCalc(ranges []*Range, board Board) (*equityCalc, error) {
+	if len(ranges) < 2 {
+		return nil, errors.New("poker: need at least 2 ranges")
+	}
+	boardSet := board.CardSet()
+	if len(board.Cards) > 5 || boardSet.Len() != len(board.Cards) {
+		return nil, fmt.Errorf("poker: invalid board %v", board.Cards)
+	}
+
+	c := &equityCalc{
+		board:   boardSet,
+		ranges:  make([][]weightedCombo, len(ranges)),
+		results: make([]EquityResult, len(ranges)),
+		values:  make([]HandValue, len(ranges)),
+	}
+	for i, r := range ranges {
+		for _, cs := range r.Exclude(boardSet).sortedKeys() {
+			c.ranges[i] = append(c.ranges[i], weightedCombo{cards: cs, weight: r.weights[cs]})
+		}
+		if len(c.ranges[i]) == 0 {
+			return nil, fmt.Errorf("poker: range %d has no combos left on the board", i)
+		}
+	}
+	return c, nil
+}
+
+// showdown records one dealt outcome with the given weight.
+func (c *equityCalc) showdown(holes []CardSet, board CardSet, weight float64) {
+	var best HandValue
+	winners := 0
+	for i, h := range holes {
+		v := h.Union(board).Evaluate()
+		c.values[i] = v
+		if v > best {
+			best, winners = v, 1
+		} else if v == best {
+			winners++
+		}
+	}
+	for i, v := range c.values {
+		if v != best {
+			continue
+		}
+		c.results[i].Equity += weight / float64(winners)
+		if winners == 1 {
+			c.results[i].Win += weight
+		} else {
+			c.results[i].Tie += weight
+		}
+	}
+	c.total += weight
+}
+
+func (c *equityCalc) finish() ([]EquityResult, error) {
+	if c.total == 0 {
+		return nil, errors.New("poker: ranges have no combination without card conflicts")
+	}
+	for i := range c.results {
+		c.results[i].Equity /= c.total
+		c.results[i].Win /= c.total
+		c.results[i].Tie /= c.total
+	}
+	return c.results, nil
+}
+
+// EnumerateEquity calculates the exact equity of each range by dealing
+// every combination of holdings and every runout of the board.
+func EnumerateEquity(ranges []*Range, board Board) ([]EquityResult, error) {
+	c, err := newEquityCalc(ranges, board)
+	if err != nil {
+		return nil, err
+	}
+
+	holes := make([]CardSet, len(ranges))
+	var deal func(i int, used CardSet, weight float64)
+	deal = func(i int, used CardSet, weight float64) {
+		if i == len(ranges) {
+			rest := FullCardSet.Difference(used).Cards()
+			eachCombination(rest, 5-c.board.Len(), func(runout CardSet) {
+				c.showdown(holes, c.board.Union(runout), weight)
+			})
+			return
+		}
+		for _, combo := range c.ranges[i] {
+			if combo.cards.Overlaps(used) {
+				continue
+			}
+			holes[i] = combo.cards
+			deal(i+1, used.Union(combo.cards), weight*combo.weight)
+		}
+	}
+	deal(0, c.board, 1)
+	return c.finish()
+}
+
+// MonteCarloEquity estimates the equity of each range from randomly dealt
+// holdings and runouts.
+func MonteCarloEquity(ranges []*Range, board Board, iterations int, rnd *rand.Rand) ([]EquityResult, error) {
+	if iterations <= 0 {
+		return nil, errors.New("poker: iterations must be positive")
+	}
+	c, err := newEquityCalc(ranges, board)
+	if err != nil {
+		return nil, err
+	}
+
+	cumulative := make([][]float64, len(c.ranges))
+	for i, combos := range c.ranges {
+		sum := 0.0
+		for _, combo := range combos {
+			sum += combo.weight
+			cumulative[i] = append(cumulative[i], sum)
+		}
+	}
+	sample := func(i int) CardSet {
+		cum := cumulative[i]
+		x := rnd.Float64() * cum[len(cum)-1]
+		return c.ranges[i][sort.SearchFloat64s(cum, x)].cards
+	}
+
+	const maxRejections = 100000
+	holes := make([]CardSet, len(ranges))
+	for n := 0; n < iterations; n++ {
+		var used CardSet
+		for rejections := 0; ; rejections++ {
+			if rejections == maxRejections {
+				return nil, errors.New("poker: ranges have no combination without card conflicts")
+			}
+			used = c.board
+			ok := true
+			for i := range holes {
+				holes[i] = sample(i)
+				if holes[i].Overlaps(used) {
+					ok = false
+					break
+				}
+				used = used.Union(holes[i])
+			}
+			if ok {
+				break
+			}
+		}
+
+		board := c.board
+		for board.Len() < 5 {
+			card := cardFromIndex(rnd.Intn(52))
+			if used.Contains(card) {
+				continue
+			}
+			used = used.Add(card)
+			board = board.Add(card)
+		}
+		c.showdown(holes, board, 1)
+	}
+	return c.finish()
+}
+
+// eachCombination calls f with every set of n cards taken from cards.
+func eachCombination(cards []Card, n int, f func(CardSet)) {
+	var pick func(start, n int, cs CardSet)
+	pick = func(start, n int, cs CardSet) {
+		if n == 0 {
+			f(cs)
+			return
+		}
+		for i := start; i <= len(cards)-n; i++ {
+			pick(i+1, n-1, cs.Add(cards[i]))
+		}
+	}
+	pick(0, n, 0)
+}
@@ -0,0 +1,124 @@
+package poker
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func mustRange(t testing.TB, s string) *Range {
+	t.Helper()
+	r, err := ParseRange(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestEnumerateEquity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ranges []string
+		board  string
+		want   []EquityResult
+	}{
+		{
+			name:   "two outs on the turn",
+			ranges: []string{"AsAh", "KsKh"},
+			board:  "2c 7d 9c Jd",
+			want:   []EquityResult{{Equity: 42.0 / 44, Win: 42.0 / 44}, {Equity: 2.0 / 44, Win: 2.0 / 44}},
+		},
+		{
+			name:   "board plays",
+			ranges: []string{"2c3c", "2d3d"},
+			board:  "As Ks Qs Js Ts",
+			want:   []EquityResult{{Equity: 0.5, Tie: 1}, {Equity: 0.5, Tie: 1}},
+		},
+		{
+			name:   "card removal between ranges",
+			ranges: []string{"AA", "AsKs"},
+			board:  "2c 7d 9h Jd 3s",
+			want:   []EquityResult{{Equity: 1, Win: 1}, {Equity: 0, Win: 0}},
+		},
+		{
+			name:   "weighted range",
+			ranges: []string{"AsAh, KsKh:0.5", "QsQh"},
+			board:  "2c 7d 9h Jd 3s",
+			want:   []EquityResult{{Equity: 1, Win: 1}, {}},
+		},
+		{
+			name:   "three ways",
+			ranges: []string{"AsAh", "KsKh", "QsQh"},
+			board:  "2c 7d 9h Jd",
+			want: []EquityResult{
+				{Equity: 38.0 / 42, Win: 38.0 / 42},
+				{Equity: 2.0 / 42, Win: 2.0 / 42},
+				{Equity: 2.0 / 42, Win: 2.0 / 42},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ranges := make([]*Range, len(tt.ranges))
+			for i, s := range tt.ranges {
+				ranges[i] = mustRange(t, s)
+			}
+			got, err := EnumerateEquity(ranges, Board{Cards: mustCards(t, tt.board)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range got {
+				if !equityNear(got[i], tt.want[i], 1e-9) {
+					t.Fatalf("range %d: want %+v, but got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEnumerateEquity_Errors(t *testing.T) {
+	t.Parallel()
+
+	board := Board{Cards: mustCards(t, "As 7d 9h")}
+	if _, err := EnumerateEquity([]*Range{mustRange(t, "AA")}, board); err == nil {
+		t.Fatalf("single range was accepted")
+	}
+	if _, err := EnumerateEquity([]*Range{mustRange(t, "AsKs"), mustRange(t, "QQ")}, board); err == nil {
+		t.Fatalf("range blocked by the board was accepted")
+	}
+	if _, err := EnumerateEquity([]*Range{mustRange(t, "KcKd"), mustRange(t, "KcKh")}, board); err == nil {
+		t.Fatalf("conflicting ranges were accepted")
+	}
+}
+
+func TestMonteCarloEquity(t *testing.T) {
+	t.Parallel()
+
+	ranges := []*Range{mustRange(t, "QQ+, AKs"), mustRange(t, "22+, A2s+, KTs+, ATo+")}
+	board := Board{Cards: mustCards(t, "Ah 8c 4d")}
+
+	want, err := EnumerateEquity(ranges, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := MonteCarloEquity(ranges, board, 100000, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range got {
+		if !equityNear(got[i], want[i], 0.01) {
+			t.Fatalf("range %d: want %+v, but got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func equityNear(a, b EquityResult, tolerance float64) bool {
+	return math.Abs(a.Equity-b.Equity) <= tolerance &&
+		math.Abs(a.Win-b.Win) <= tolerance &&
+		math.Abs(a.Tie-b.Tie) <= tolerance
+}
@@ -0,0 +1,148 @@
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// HandValue orders hands the same way as Hand.Compare: the higher value is
+// the stronger hand and equal values draw. The hand rank is kept in the top
+// bits and the ranks deciding ties in the five nibbles below it.
+type HandValue uint32
+
+func (v HandValue) Rank() HandRank {
+	return HandRank(v >> 20)
+}
+
+// Evaluate returns the value of the best five card hand that can be made
+// from the cards in the set. Sets of fewer than five cards are valued by
+// the cards they have.
+func (cs CardSet) Evaluate() HandValue {
+	var all uint16
+	var masks [4]uint16
+	for s := 0; s < 4; s++ {
+		masks[s] = cs.suitMask(s)
+		all |= masks[s]
+	}
+
+	flush := -1
+	for s := 0; s < 4; s++ {
+		if bits.OnesCount16(masks[s]) >= 5 {
+			flush = s
+			if hi := straightHigh(masks[s]); hi != 0 {
+				if hi == Ace {
+					return handValue(RoyalFlush) | kickers(rankBit(hi), 1, 4)
+				}
+				return handValue(StraightFlush) | kickers(rankBit(hi), 1, 4)
+			}
+		}
+	}
+
+	var quads, trips, pairs uint16
+	for i := uint(0); i < 13; i++ {
+		n := masks[0]>>i&1 + masks[1]>>i&1 + masks[2]>>i&1 + masks[3]>>i&1
+		switch n {
+		case 4:
+			quads |= 1 << i
+		case 3:
+			trips |= 1 << i
+		case 2:
+			pairs |= 1 << i
+		}
+	}
+
+	if quads != 0 {
+		q := topBit(quads)
+		return handValue(FourOfAKind) | kickers(q, 1, 4) | kickers(all&^q, 1, 3)
+	}
+	if trips != 0 {
+		t := topBit(trips)
+		if rest := trips&^t | pairs; rest != 0 {
+			return handValue(FullHouse) | kickers(t, 1, 4) | kickers(topBit(rest), 1, 3)
+		}
+	}
+	if flush >= 0 {
+		return handValue(Flush) | kickers(masks[flush], 5, 4)
+	}
+	if hi := straightHigh(all); hi != 0 {
+		return handValue(Straight) | kickers(rankBit(hi), 1, 4)
+	}
+	if trips != 0 {
+		t := topBit(trips)
+		return handValue(ThreeOfAKind) | kickers(t, 1, 4) | kickers(all&^t, 2, 3)
+	}
+	if pairs != 0 {
+		p := topBit(pairs)
+		if rest := pairs &^ p; rest != 0 {
+			p2 := topBit(rest)
+			return handValue(TwoPair) | kickers(p, 1, 4) | kickers(p2, 1, 3) | kickers(all&^p&^p2, 1, 2)
+		}
+		return handValue(OnePair) | kickers(p, 1, 4) | kickers(all&^p, 3, 3)
+	}
+	return handValue(HighCard) | kickers(all, 5, 4)
+}
+
+func handValue(r HandRank) HandValue {
+	return HandValue(r) << 20
+}
+
+// kickers packs the top n ranks of mask into the nibbles from pos downwards.
+func kickers(mask uint16, n int, pos uint) HandValue {
+	var v HandValue
+	for ; n > 0 && mask != 0; n-- {
+		i := 15 - bits.LeadingZeros16(mask)
+		v |= HandValue(i+int(Deuce)) << (4 * pos)
+		mask &^= 1 << uint(i)
+		pos--
+	}
+	return v
+}
+
+func rankBit(r CardRank) uint16 {
+	return 1 << uint(r-Deuce)
+}
+
+func topBit(mask uint16) uint16 {
+	return 1 << uint(15-bits.LeadingZeros16(mask))
+}
+
+// straightHigh returns the top card of the highest straight in mask, or 0.
+func straightHigh(mask uint16) CardRank {
+	// for ace to five straight
+	m := mask<<1 | mask>>12&1
+	for top := 13; top >= 4; top-- {
+		if m>>uint(top-4)&0x1f == 0x1f {
+			return CardRank(top + 1)
+		}
+	}
+	return 0
+}
+
+// BestHand returns the strongest five card hand that can be made from cards.
+func BestHand(cards []Card) (*Hand, error) {
+	if len(cards) < 5 {
+		return nil, fmt.Errorf("poker: need at least 5 cards, got %d", len(cards))
+	}
+	if NewCardSet(cards...).Len() != len(cards) {
+		return nil, fmt.Errorf("poker: invalid or duplicated card in %v", cards)
+	}
+
+	var best []Card
+	var bestValue HandValue
+	sub := make([]Card, 5)
+	var pick func(start, n int)
+	pick = func(start, n int) {
+		if n == 5 {
+			if v := NewCardSet(sub...).Evaluate(); best == nil || v > bestValue {
+				best, bestValue = append([]Card(nil), sub...), v
+			}
+			return
+		}
+		for i := start; i <= len(cards)-(5-n); i++ {
+			sub[n] = cards[i]
+			pick(i+1, n+1)
+		}
+	}
+	pick(0, 0)
+	return NewHand(best), nil
+}
@@ -0,0 +1,178 @@
+package poker
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func mustCards(t testing.TB, s string) []Card {
+	t.Helper()
+	cards, err := ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestCardSet_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		cards string
+		want  HandRank
+	}{
+		{name: "royal flush", cards: "As Ks Qs Js Ts 2d 3c", want: RoyalFlush},
+		{name: "straight flush", cards: "9h 8h 7h 6h 5h Kh Ah", want: StraightFlush},
+		{name: "wheel straight flush", cards: "Ad 2d 3d 4d 5d Kc Kh", want: StraightFlush},
+		{name: "four of a kind", cards: "7s 7c 7d 7h As Ad Ah", want: FourOfAKind},
+		{name: "full house from two trips", cards: "7s 7c 7d 4h 4s 4d Ah", want: FullHouse},
+		{name: "flush over straight", cards: "2c 5c 9c Jc Qc Td 8h", want: Flush},
+		{name: "straight", cards: "Ad 2c 3h 4s 5d Kc 9h", want: Straight},
+		{name: "three of a kind", cards: "Qs Qc Qd 9h 4s 2d 7h", want: ThreeOfAKind},
+		{name: "two pair from three pairs", cards: "Qs Qc 9d 9h 4s 4d 7h", want: TwoPair},
+		{name: "one pair", cards: "Qs Qc 9d 8h 4s 3d 7h", want: OnePair},
+		{name: "high card", cards: "As Qc 9d 8h 4s 3d 7h", want: HighCard},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewCardSet(mustCards(t, tt.cards)...).Evaluate().Rank(); got != tt.want {
+				t.Fatalf("want is %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCardSet_Evaluate_MatchesCompare(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(1))
+	deal := func() []Card {
+		cards := FullCardSet.Cards()
+		rnd.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+		return cards[:5]
+	}
+	for i := 0; i < 20000; i++ {
+		a, b := deal(), deal()
+		ha, hb := NewHand(append([]Card(nil), a...)), NewHand(append([]Card(nil), b...))
+		va, vb := NewCardSet(a...).Evaluate(), NewCardSet(b...).Evaluate()
+
+		if va.Rank() != ha.Rank() {
+			t.Fatalf("%v: want is %s, but got %s", a, ha.Rank(), va.Rank())
+		}
+		want := ha.Compare(hb)
+		got := Draw
+		if va > vb {
+			got = Win
+		} else if va < vb {
+			got = Lose
+		}
+		if got != want {
+			t.Fatalf("%v vs %v: want is %s, but got %s", a, b, want, got)
+		}
+	}
+}
+
+func TestCardSet_Evaluate_StraightFlushes(t *testing.T) {
+	t.Parallel()
+
+	// every straight flush, from the wheel up to the royal flush of each suit
+	var hands [][]Card
+	for _, s := range []Suit{Spade, Club, Diamond, Heart} {
+		for top := Five; top <= Ace; top++ {
+			var cards []Card
+			for r := top; r > top-5; r-- {
+				rank := r
+				if rank < Deuce {
+					rank = Ace
+				}
+				cards = append(cards, Card{Suit: s, Rank: rank})
+			}
+			hands = append(hands, cards)
+		}
+	}
+	if len(hands) != 40 {
+		t.Fatalf("want is 40 straight flushes, but got %d", len(hands))
+	}
+
+	for i, a := range hands {
+		want := StraightFlush
+		if i%10 == 9 {
+			want = RoyalFlush
+		}
+		ha := NewHand(append([]Card(nil), a...))
+		va := NewCardSet(a...).Evaluate()
+		if ha.Rank() != want || va.Rank() != want {
+			t.Fatalf("%v: want is %s, but got %s and %s", a, want, ha.Rank(), va.Rank())
+		}
+		for j, b := range hands {
+			want := Draw
+			if i%10 > j%10 {
+				want = Win
+			} else if i%10 < j%10 {
+				want = Lose
+			}
+			if got := ha.Compare(NewHand(append([]Card(nil), b...))); got != want {
+				t.Fatalf("%v vs %v: want is %s, but got %s", a, b, want, got)
+			}
+			vb := NewCardSet(b...).Evaluate()
+			if (va > vb) != (want == Win) || (va < vb) != (want == Lose) {
+				t.Fatalf("%v vs %v: want is %s, but got values %d and %d", a, b, want, va, vb)
+			}
+		}
+	}
+}
+
+func TestBestHand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cards    string
+		want     string
+		wantRank HandRank
+		wantErr  bool
+	}{
+		{name: "flush", cards: "Ah 2h 5h 9h Kh 5s 5c", want: "Ah Kh 9h 5h 2h", wantRank: Flush},
+		{name: "full house", cards: "Ah As 5h 5d 5c Ks Kd", want: "5h 5d 5c Ah As", wantRank: FullHouse},
+		{name: "wheel", cards: "Ah 2s 3d 4c 5c Jd Jh", want: "5c 4c 3d 2s Ah", wantRank: Straight},
+		{name: "steel wheel", cards: "3s 4s 5s Kd Qc As 2s", want: "5s 4s 3s 2s As", wantRank: StraightFlush},
+		{name: "six high straight flush", cards: "3s 4s 5s Kd Qc 2s 6s", want: "6s 5s 4s 3s 2s", wantRank: StraightFlush},
+		{name: "royal flush over steel wheel", cards: "As Ks Qs Js Ts 2s 3s 4s 5s", want: "As Ks Qs Js Ts", wantRank: RoyalFlush},
+		{name: "five cards", cards: "Ah 2s 3d 4c 9c", want: "Ah 9c 4c 3d 2s", wantRank: HighCard},
+		{name: "too few cards", cards: "Ah 2s 3d 4c", wantErr: true},
+		{name: "duplicated cards", cards: "Ah Ah 3d 4c 9c", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := BestHand(mustCards(t, tt.cards))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if h.Rank() != tt.wantRank {
+				t.Fatalf("want is %s, but got %s", tt.wantRank, h.Rank())
+			}
+			if diff := cmp.Diff(h.Cards, mustCards(t, tt.want)); diff != "" {
+				t.Fatalf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func BenchmarkCardSet_Evaluate(b *testing.B) {
+	cs := NewCardSet(mustCards(b, "As Kd 9c 9h 4s 3d 7h")...)
+	for i := 0; i < b.N; i++ {
+		cs.Evaluate()
+	}
+}
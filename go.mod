@@ -2,4 +2,7 @@ module github.com/yuzuy/poker
 
 go 1.18
 
-require github.com/google/go-cmp v0.5.8
+require (
+	github.com/google/go-cmp v0.5.8
+	github.com/gorilla/websocket v1.5.0
+)
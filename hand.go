@@ -1,6 +1,8 @@
 package poker
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
 )
 
@@ -36,6 +38,40 @@ func (r HandRank) String() string {
 	return handRankToName[r]
 }
 
+func (r HandRank) MarshalText() ([]byte, error) {
+	name, ok := handRankToName[r]
+	if !ok {
+		return nil, fmt.Errorf("poker: invalid hand rank %d", int(r))
+	}
+	return []byte(name), nil
+}
+
+func (r *HandRank) UnmarshalText(text []byte) error {
+	for rank, name := range handRankToName {
+		if name == string(text) {
+			*r = rank
+			return nil
+		}
+	}
+	return fmt.Errorf("poker: invalid hand rank %q", text)
+}
+
+func (r HandRank) MarshalJSON() ([]byte, error) {
+	text, err := r.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (r *HandRank) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return r.UnmarshalText([]byte(s))
+}
+
 type Hand struct {
 	rank  HandRank
 	Cards []Card
@@ -47,6 +83,66 @@ func NewHand(cards []Card) *Hand {
 	return h
 }
 
+func ParseHand(s string) (*Hand, error) {
+	cards, err := ParseCards(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) != 5 {
+		return nil, fmt.Errorf("poker: hand must have 5 cards, got %d", len(cards))
+	}
+	return NewHand(cards), nil
+}
+
+func (h Hand) MarshalText() ([]byte, error) {
+	s, err := formatCards(h.Cards)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+func (h *Hand) UnmarshalText(text []byte) error {
+	parsed, err := ParseHand(string(text))
+	if err != nil {
+		return err
+	}
+	*h = *parsed
+	return nil
+}
+
+func (h Hand) MarshalJSON() ([]byte, error) {
+	text, err := h.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (h *Hand) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return h.UnmarshalText([]byte(s))
+}
+
+func (h Hand) MarshalBinary() ([]byte, error) {
+	return marshalCardsBinary(h.Cards)
+}
+
+func (h *Hand) UnmarshalBinary(data []byte) error {
+	if len(data) != 5 {
+		return fmt.Errorf("poker: hand must have 5 cards, got %d", len(data))
+	}
+	cards, err := unmarshalCardsBinary(data)
+	if err != nil {
+		return err
+	}
+	*h = *NewHand(cards)
+	return nil
+}
+
 func (h *Hand) sortByCardRank() {
 	sort.SliceStable(h.Cards, func(i, j int) bool {
 		return h.Cards[i].Rank > h.Cards[j].Rank
@@ -107,7 +203,8 @@ func (h *Hand) Rank() HandRank {
 		h.rank = Flush
 		if h.isStraight() {
 			h.rank = StraightFlush
-			if h.Cards[0].Rank == Ace {
+			// the ace of a wheel plays low
+			if h.Cards[0].Rank == Ace && h.Cards[1].Rank == King {
 				h.rank = RoyalFlush
 			}
 		}
@@ -129,13 +226,14 @@ func (h *Hand) Rank() HandRank {
 
 		if i < 3 {
 			if h.Cards[i].Rank == h.Cards[i+1].Rank && h.Cards[i].Rank == h.Cards[i+2].Rank {
+				pair := h.rank == OnePair
 				h.rank = ThreeOfAKind
 				if i == 0 {
 					if h.Cards[3].Rank == h.Cards[4].Rank {
 						h.rank = FullHouse
 					}
 				}
-				if i == 2 && h.rank == OnePair {
+				if i == 2 && pair {
 					h.rank = FullHouse
 				}
 				return h.rank
@@ -144,7 +242,8 @@ func (h *Hand) Rank() HandRank {
 
 		if h.Cards[i].Rank == h.Cards[i+1].Rank {
 			if h.rank == OnePair {
-				return TwoPair
+				h.rank = TwoPair
+				return h.rank
 			}
 			h.rank = OnePair
 			i++
@@ -1,6 +1,7 @@
 package poker
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -386,6 +387,19 @@ func TestHand_Rank(t *testing.T) {
 			},
 			want: StraightFlush,
 		},
+		{
+			name: "steel wheel",
+			hand: &Hand{
+				Cards: []Card{
+					{Suit: Spade, Rank: Ace},
+					{Suit: Spade, Rank: Deuce},
+					{Suit: Spade, Rank: Three},
+					{Suit: Spade, Rank: Four},
+					{Suit: Spade, Rank: Five},
+				},
+			},
+			want: StraightFlush,
+		},
 		{
 			name: "four of a kind",
 			hand: &Hand{
@@ -490,6 +504,19 @@ func TestHand_Rank(t *testing.T) {
 			},
 			want: HighCard,
 		},
+		{
+			name: "full house with pair above trips",
+			hand: &Hand{
+				Cards: []Card{
+					{Suit: Spade, Rank: Nine},
+					{Suit: Club, Rank: Nine},
+					{Suit: Spade, Rank: Five},
+					{Suit: Club, Rank: Five},
+					{Suit: Heart, Rank: Five},
+				},
+			},
+			want: FullHouse,
+		},
 	}
 
 	for _, tt := range tests {
@@ -503,6 +530,24 @@ func TestHand_Rank(t *testing.T) {
 	}
 }
 
+func TestNewHand_Rank(t *testing.T) {
+	t.Parallel()
+
+	h := NewHand([]Card{
+		{Suit: Spade, Rank: Nine},
+		{Suit: Club, Rank: Nine},
+		{Suit: Spade, Rank: Five},
+		{Suit: Club, Rank: Five},
+		{Suit: Heart, Rank: Deuce},
+	})
+	if h.Rank() != TwoPair {
+		t.Fatalf("want is %s, but got %s", TwoPair, h.Rank())
+	}
+	if h.Cards[4].Rank != Deuce {
+		t.Fatalf("kicker is not sorted last: %v", h.Cards)
+	}
+}
+
 func TestHand_Compare_HandRank(t *testing.T) {
 	t.Parallel()
 
@@ -1042,6 +1087,26 @@ func TestHand_Compare_CardRank(t *testing.T) {
 			},
 			want: Draw,
 		},
+		{
+			name: "royal flush win to steel wheel",
+			hand: NewHand([]Card{
+				{Suit: Spade, Rank: Ace}, {Suit: Spade, Rank: King}, {Suit: Spade, Rank: Queen}, {Suit: Spade, Rank: Jack}, {Suit: Spade, Rank: Ten},
+			}),
+			rival: NewHand([]Card{
+				{Suit: Heart, Rank: Ace}, {Suit: Heart, Rank: Deuce}, {Suit: Heart, Rank: Three}, {Suit: Heart, Rank: Four}, {Suit: Heart, Rank: Five},
+			}),
+			want: Win,
+		},
+		{
+			name: "six high straight flush win to steel wheel",
+			hand: NewHand([]Card{
+				{Suit: Spade, Rank: Deuce}, {Suit: Spade, Rank: Three}, {Suit: Spade, Rank: Four}, {Suit: Spade, Rank: Five}, {Suit: Spade, Rank: Six},
+			}),
+			rival: NewHand([]Card{
+				{Suit: Heart, Rank: Ace}, {Suit: Heart, Rank: Deuce}, {Suit: Heart, Rank: Three}, {Suit: Heart, Rank: Four}, {Suit: Heart, Rank: Five},
+			}),
+			want: Win,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1065,3 +1130,90 @@ func TestHand_Compare_CardRank(t *testing.T) {
 		})
 	}
 }
+
+func TestHand_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	h, err := ParseHand("Tc Jc Qh Kd As")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"As Kd Qh Jc Tc"`; string(b) != want {
+		t.Fatalf("want %s, but got %s", want, b)
+	}
+
+	var got Hand
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Rank() != Straight {
+		t.Fatalf("want %v, but got %v", Straight, got.Rank())
+	}
+	if diff := cmp.Diff(got.Cards, h.Cards); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+
+	if err := json.Unmarshal([]byte(`"As Kd"`), &got); err == nil {
+		t.Fatalf("hand with 2 cards was unmarshaled")
+	}
+}
+
+func TestHand_MarshalBinary(t *testing.T) {
+	t.Parallel()
+
+	h, err := ParseHand("9s 9c 9d 4h 4s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 5 {
+		t.Fatalf("want 5 bytes, but got %d", len(b))
+	}
+
+	var got Hand
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if got.Rank() != FullHouse {
+		t.Fatalf("want %v, but got %v", FullHouse, got.Rank())
+	}
+	if diff := cmp.Diff(got.Cards, h.Cards); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestHandRank_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for r := HighCard; r <= RoyalFlush; r++ {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `"` + r.String() + `"`; string(b) != want {
+			t.Fatalf("want %s, but got %s", want, b)
+		}
+		var got HandRank
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != r {
+			t.Fatalf("want %v, but got %v", r, got)
+		}
+	}
+
+	if _, err := json.Marshal(HandRank(0)); err == nil {
+		t.Fatalf("invalid hand rank was marshaled")
+	}
+	var got HandRank
+	if err := json.Unmarshal([]byte(`"five of a kind"`), &got); err == nil {
+		t.Fatalf("invalid hand rank was unmarshaled")
+	}
+}
@@ -0,0 +1,167 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HandClass is one of the 169 strategically distinct starting hands such as
+// AA, AKs or AKo. Pairs are never suited.
+type HandClass struct {
+	High   CardRank
+	Low    CardRank
+	Suited bool
+}
+
+const NumHandClasses = 169
+
+func ParseHandClass(s string) (HandClass, error) {
+	c, err := parseClass(s)
+	if err != nil {
+		return HandClass{}, fmt.Errorf("poker: %w", err)
+	}
+	if c.high != c.low && c.suit == anySuitedness {
+		return HandClass{}, fmt.Errorf("poker: hand class %q must be suited or offsuit", s)
+	}
+	return HandClass{High: c.high, Low: c.low, Suited: c.suit == suited}, nil
+}
+
+func (ph PersonalHand) Class() (HandClass, error) {
+	if len(ph.Cards) != 2 || ph.CardSet().Len() != 2 {
+		return HandClass{}, fmt.Errorf("poker: invalid holding %v", ph.Cards)
+	}
+	a, b := ph.Cards[0], ph.Cards[1]
+	if a.Rank < b.Rank {
+		a, b = b, a
+	}
+	return HandClass{High: a.Rank, Low: b.Rank, Suited: a.Rank != b.Rank && a.Suit == b.Suit}, nil
+}
+
+func (c HandClass) IsPair() bool {
+	return c.High == c.Low
+}
+
+func (c HandClass) valid() bool {
+	return c.High.valid() && c.Low.valid() && c.High >= c.Low && !(c.IsPair() && c.Suited)
+}
+
+func (c HandClass) String() string {
+	s := rankChar(c.High) + rankChar(c.Low)
+	switch {
+	case c.IsPair():
+		return s
+	case c.Suited:
+		return s + "s"
+	default:
+		return s + "o"
+	}
+}
+
+// NumCombos returns how many two-card holdings belong to the class: 6 for a
+// pair, 4 when suited and 12 when offsuit.
+func (c HandClass) NumCombos() int {
+	switch {
+	case c.IsPair():
+		return 6
+	case c.Suited:
+		return 4
+	default:
+		return 12
+	}
+}
+
+func (c HandClass) combos() []CardSet {
+	var combos []CardSet
+	for i, s1 := range suits {
+		for j, s2 := range suits {
+			if c.IsPair() && j <= i {
+				continue
+			}
+			if !c.IsPair() && c.Suited != (s1 == s2) {
+				continue
+			}
+			combos = append(combos, NewCardSet(Card{Suit: s1, Rank: c.High}, Card{Suit: s2, Rank: c.Low}))
+		}
+	}
+	return combos
+}
+
+func (c HandClass) Combos() []PersonalHand {
+	sets := c.combos()
+	hands := make([]PersonalHand, len(sets))
+	for i, cs := range sets {
+		hands[i] = comboHand(cs)
+	}
+	sort.Slice(hands, func(i, j int) bool {
+		return comboLess(hands[i], hands[j])
+	})
+	return hands
+}
+
+// GridPosition returns the cell of the class in the usual 13x13 grid with
+// aces in row and column 0, pairs on the diagonal, suited hands above it and
+// offsuit hands below it.
+func (c HandClass) GridPosition() (row, col int) {
+	hi, lo := int(Ace-c.High), int(Ace-c.Low)
+	if c.Suited {
+		return hi, lo
+	}
+	return lo, hi
+}
+
+// Index returns the position of the class in HandClasses.
+func (c HandClass) Index() int {
+	row, col := c.GridPosition()
+	return row*13 + col
+}
+
+func HandClassAt(row, col int) (HandClass, error) {
+	if row < 0 || row >= 13 || col < 0 || col >= 13 {
+		return HandClass{}, fmt.Errorf("poker: grid position (%d, %d) out of range", row, col)
+	}
+	a, b := Ace-CardRank(row), Ace-CardRank(col)
+	switch {
+	case row < col:
+		return HandClass{High: a, Low: b, Suited: true}, nil
+	case row > col:
+		return HandClass{High: b, Low: a}, nil
+	default:
+		return HandClass{High: a, Low: a}, nil
+	}
+}
+
+// HandClasses returns all 169 classes in grid order, row by row.
+func HandClasses() []HandClass {
+	classes := make([]HandClass, 0, NumHandClasses)
+	for row := 0; row < 13; row++ {
+		for col := 0; col < 13; col++ {
+			c, _ := HandClassAt(row, col)
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+func (r *Range) AddClass(c HandClass, weight float64) error {
+	if !c.valid() {
+		return fmt.Errorf("poker: invalid hand class %+v", c)
+	}
+	for _, cs := range c.combos() {
+		r.set(cs, weight)
+	}
+	return nil
+}
+
+// ClassFrequencies aggregates the range by hand class. Each value is the
+// weighted share of the class combos included in the range.
+func (r *Range) ClassFrequencies() map[HandClass]float64 {
+	freqs := map[HandClass]float64{}
+	for cs, w := range r.weights {
+		c, _ := comboHand(cs).Class()
+		freqs[c] += w
+	}
+	for c := range freqs {
+		freqs[c] /= float64(c.NumCombos())
+	}
+	return freqs
+}
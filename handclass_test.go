@@ -0,0 +1,151 @@
+package poker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPersonalHand_Class(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cards   string
+		want    string
+		wantErr bool
+	}{
+		{name: "pair", cards: "Ah As", want: "AA"},
+		{name: "suited", cards: "Kd Ad", want: "AKs"},
+		{name: "offsuit", cards: "7c Th", want: "T7o"},
+		{name: "one card", cards: "7c", wantErr: true},
+		{name: "same card", cards: "7c 7c", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := PersonalHand{Cards: mustCards(t, tt.cards)}.Class()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want {
+				t.Fatalf("want %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseHandClass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s         string
+		want      HandClass
+		wantCombo int
+		wantErr   bool
+	}{
+		{s: "AA", want: HandClass{High: Ace, Low: Ace}, wantCombo: 6},
+		{s: "AKs", want: HandClass{High: Ace, Low: King, Suited: true}, wantCombo: 4},
+		{s: "72o", want: HandClass{High: Seven, Low: Deuce}, wantCombo: 12},
+		{s: "AK", wantErr: true},
+		{s: "AAs", wantErr: true},
+		{s: "A", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseHandClass(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatalf("want and got are different(-got +want): %s", diff)
+			}
+			if got.NumCombos() != tt.wantCombo || len(got.Combos()) != tt.wantCombo {
+				t.Fatalf("want %d combos, but got %d and %d", tt.wantCombo, got.NumCombos(), len(got.Combos()))
+			}
+			for _, h := range got.Combos() {
+				if c, _ := h.Class(); c != got {
+					t.Fatalf("combo %v belongs to %v", h.Cards, c)
+				}
+			}
+		})
+	}
+}
+
+func TestHandClasses(t *testing.T) {
+	t.Parallel()
+
+	classes := HandClasses()
+	if len(classes) != NumHandClasses {
+		t.Fatalf("want %d classes, but got %d", NumHandClasses, len(classes))
+	}
+	seen := map[HandClass]bool{}
+	combos := 0
+	for i, c := range classes {
+		if seen[c] {
+			t.Fatalf("class %v duplicated", c)
+		}
+		seen[c] = true
+		combos += c.NumCombos()
+		if c.Index() != i {
+			t.Fatalf("class %v has index %d at position %d", c, c.Index(), i)
+		}
+		row, col := c.GridPosition()
+		if got, _ := HandClassAt(row, col); got != c {
+			t.Fatalf("class %v is at (%d, %d) but HandClassAt returned %v", c, row, col, got)
+		}
+	}
+	if combos != 1326 {
+		t.Fatalf("want 1326 combos, but got %d", combos)
+	}
+
+	grid := []struct {
+		row, col int
+		want     string
+	}{
+		{0, 0, "AA"}, {0, 1, "AKs"}, {1, 0, "AKo"}, {12, 12, "22"}, {5, 9, "95s"}, {9, 5, "95o"},
+	}
+	for _, g := range grid {
+		if got, _ := HandClassAt(g.row, g.col); got.String() != g.want {
+			t.Fatalf("(%d, %d): want %s, but got %s", g.row, g.col, g.want, got)
+		}
+	}
+	if _, err := HandClassAt(13, 0); err == nil {
+		t.Fatalf("out of range position was accepted")
+	}
+}
+
+func TestRange_ClassFrequencies(t *testing.T) {
+	t.Parallel()
+
+	r := mustRange(t, "AA, AKs:0.5, AsKd, AcKh")
+	want := map[HandClass]float64{
+		{High: Ace, Low: Ace}:                 1,
+		{High: Ace, Low: King, Suited: true}:  0.5,
+		{High: Ace, Low: King, Suited: false}: 2.0 / 12,
+	}
+	if diff := cmp.Diff(r.ClassFrequencies(), want); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+
+	if err := r.AddClass(HandClass{High: Queen, Low: Jack}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 6+4+2+12 {
+		t.Fatalf("want %d combos, but got %d", 6+4+2+12, r.Len())
+	}
+	if err := r.AddClass(HandClass{High: Queen, Low: Queen, Suited: true}, 1); err == nil {
+		t.Fatalf("suited pair was accepted")
+	}
+}
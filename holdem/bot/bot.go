@@ -0,0 +1,122 @@
+// Package bot provides reference agents for automated Hold'em games.
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+// Random picks its actions at random, raising any legal amount.
+type Random struct {
+	rnd *rand.Rand
+}
+
+func NewRandom(rnd *rand.Rand) *Random {
+	return &Random{rnd: rnd}
+}
+
+func (b *Random) Act(v *holdem.View) holdem.Action {
+	canRaise := v.MaxBet() > v.Bets[v.Seat]+v.ToCall
+	switch n := b.rnd.Intn(3); {
+	case n == 0 && v.ToCall > 0:
+		return holdem.Action{Kind: holdem.Fold}
+	case n == 2 && canRaise:
+		low := v.MinRaise
+		if low > v.MaxBet() {
+			low = v.MaxBet()
+		}
+		return v.BetTo(low + b.rnd.Intn(v.MaxBet()-low+1))
+	default:
+		return v.CheckOrCall()
+	}
+}
+
+// CallingStation never folds and never raises.
+type CallingStation struct{}
+
+func (CallingStation) Act(v *holdem.View) holdem.Action {
+	return v.CheckOrCall()
+}
+
+// TightAggressive plays few hands before the flop and bets its made hands
+// after it.
+type TightAggressive struct {
+	// Raise holds the hands raised before the flop, and Call the ones
+	// only calling a raise.
+	Raise *poker.Range
+	Call  *poker.Range
+}
+
+func NewTightAggressive() *TightAggressive {
+	raise, _ := poker.ParseRange("77+, A9s+, KTs+, QJs, AJo+, KQo")
+	call, _ := poker.ParseRange("22+, A2s+, K9s+, QTs+, JTs, T9s, 98s, ATo+, KJo+")
+	return &TightAggressive{Raise: raise, Call: call}
+}
+
+func (b *TightAggressive) Act(v *holdem.View) holdem.Action {
+	current := v.Bets[v.Seat] + v.ToCall
+	if v.Street == holdem.Preflop {
+		switch {
+		case b.Raise.Weight(v.Hole) > 0:
+			// open to three big blinds, or three times a raise
+			return v.BetTo(3 * current)
+		case b.Call.Weight(v.Hole) > 0 && v.ToCall <= 4*v.BigBlind:
+			return v.CheckOrCall()
+		default:
+			return v.CheckOrFold()
+		}
+	}
+
+	switch rank := v.Hole.CardSet().Union(v.Board.CardSet()).Evaluate().Rank(); {
+	case rank >= poker.TwoPair:
+		return v.BetTo(current + v.Pot*2/3)
+	case rank == poker.OnePair && 3*v.ToCall <= v.Pot:
+		return v.CheckOrCall()
+	default:
+		return v.CheckOrFold()
+	}
+}
+
+// Equity compares its equity against random hands of the players still in
+// with the price it is offered.
+type Equity struct {
+	iterations int
+	rnd        *rand.Rand
+	random     *poker.Range
+}
+
+func NewEquity(iterations int, rnd *rand.Rand) *Equity {
+	random := poker.NewRange()
+	for _, c := range poker.HandClasses() {
+		_ = random.AddClass(c, 1)
+	}
+	return &Equity{iterations: iterations, rnd: rnd, random: random}
+}
+
+func (b *Equity) Act(v *holdem.View) holdem.Action {
+	hole := poker.NewRange()
+	if err := hole.Add(v.Hole, 1); err != nil {
+		return v.CheckOrFold()
+	}
+	ranges := []*poker.Range{hole}
+	for i := 1; i < v.Active(); i++ {
+		ranges = append(ranges, b.random)
+	}
+	results, err := poker.MonteCarloEquity(ranges, v.Board, b.iterations, b.rnd)
+	if err != nil {
+		return v.CheckOrFold()
+	}
+	equity := results[0].Equity
+
+	players := float64(len(ranges))
+	switch {
+	case equity > 1.5/players:
+		return v.BetTo(v.Bets[v.Seat] + v.ToCall + v.Pot)
+	case equity*float64(v.Pot+v.ToCall) >= float64(v.ToCall):
+		return v.CheckOrCall()
+	default:
+		return v.CheckOrFold()
+	}
+}
@@ -0,0 +1,132 @@
+package bot
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+func mustHand(t *testing.T, s string) poker.PersonalHand {
+	t.Helper()
+	cards, err := poker.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return poker.PersonalHand{Cards: cards}
+}
+
+func mustBoard(t *testing.T, s string) poker.Board {
+	t.Helper()
+	if s == "" {
+		return poker.Board{}
+	}
+	cards, err := poker.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return poker.Board{Cards: cards}
+}
+
+// preflop returns the view of the button facing the big blind heads up.
+func preflop(t *testing.T, hole string) *holdem.View {
+	return &holdem.View{
+		Seat: 0, Hole: mustHand(t, hole), Button: 0,
+		Pot: 3, Stacks: []int{99, 98}, Bets: []int{1, 2}, Folded: []bool{false, false},
+		ToCall: 1, MinRaise: 4, BigBlind: 2,
+	}
+}
+
+// facingBet returns the view of a player facing a bet of 10 into 20 on
+// board.
+func facingBet(t *testing.T, hole, board string) *holdem.View {
+	b := mustBoard(t, board)
+	return &holdem.View{
+		Seat: 1, Hole: mustHand(t, hole), Board: b, Street: holdem.Street(len(b.Cards) - 2),
+		Pot: 30, Stacks: []int{80, 90}, Bets: []int{10, 0}, Folded: []bool{false, false},
+		ToCall: 10, MinRaise: 20, BigBlind: 2,
+	}
+}
+
+func TestCallingStation_Act(t *testing.T) {
+	t.Parallel()
+	v := facingBet(t, "7c 2d", "As Ks Qs")
+	if diff := cmp.Diff(CallingStation{}.Act(v), holdem.Action{Kind: holdem.Call}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	v.ToCall = 0
+	if diff := cmp.Diff(CallingStation{}.Act(v), holdem.Action{Kind: holdem.Check}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestRandom_Act(t *testing.T) {
+	t.Parallel()
+	b := NewRandom(rand.New(rand.NewSource(1)))
+	seen := map[holdem.ActionKind]bool{}
+	for i := 0; i < 100; i++ {
+		v := facingBet(t, "7c 2d", "As Ks Qs")
+		a := b.Act(v)
+		seen[a.Kind] = true
+		if a.Kind == holdem.Raise && (a.Amount < v.MinRaise || a.Amount > v.MaxBet()) {
+			t.Fatalf("want is a raise between %d and %d, but got %v", v.MinRaise, v.MaxBet(), a)
+		}
+		if a.Kind == holdem.Check || a.Kind == holdem.Bet {
+			t.Fatalf("want is fold, call or raise facing a bet, but got %v", a)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("want is fold, call and raise taken, but got %v", seen)
+	}
+}
+
+func TestTightAggressive_Act(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		v    *holdem.View
+		want holdem.Action
+	}{
+		{name: "raise aces", v: preflop(t, "As Ah"), want: holdem.Action{Kind: holdem.Raise, Amount: 6}},
+		{name: "fold seven deuce", v: preflop(t, "7c 2d"), want: holdem.Action{Kind: holdem.Fold}},
+		{name: "call small pair", v: preflop(t, "3c 3d"), want: holdem.Action{Kind: holdem.Call}},
+		{name: "raise a set", v: facingBet(t, "9c 9d", "9s 5h 2c"), want: holdem.Action{Kind: holdem.Raise, Amount: 30}},
+		{name: "call top pair", v: facingBet(t, "Ac Jd", "As 5h 2c"), want: holdem.Action{Kind: holdem.Call}},
+		{name: "fold air", v: facingBet(t, "Tc Jd", "As 5h 2c"), want: holdem.Action{Kind: holdem.Fold}},
+	}
+	b := NewTightAggressive()
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(b.Act(tt.v), tt.want); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEquity_Act(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		v    *holdem.View
+		want holdem.ActionKind
+	}{
+		{name: "raise the nuts", v: facingBet(t, "Ah Kh", "Qh Jh Th 2c"), want: holdem.Raise},
+		{name: "call a draw", v: facingBet(t, "8h 7h", "Kh 6h 2c"), want: holdem.Call},
+		{name: "fold air", v: facingBet(t, "3c 2d", "As Ks Qh Jh"), want: holdem.Fold},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewEquity(2000, rand.New(rand.NewSource(1)))
+			if got := b.Act(tt.v); got.Kind != tt.want {
+				t.Errorf("want is %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package holdem
+
+import "github.com/yuzuy/poker"
+
+// Event is something that happened in a hand. It is one of HandStarted,
+// BlindsPosted, CardsDealt, ActionTaken, StreetDealt, BetReturned,
+// Showdown, PotAwarded and HandEnded.
+type Event interface {
+	event()
+}
+
+// HandStarted opens every hand. Stacks holds the chips of each seat before
+// the antes and blinds, and Deck the cards in the order they are dealt.
+type HandStarted struct {
+	Config Config
+	Button int
+	Stacks []int
+	Deck   []poker.Card
+}
+
+// BlindsPosted holds the antes of each seat and the blinds, which may be
+// short when a player has fewer chips.
+type BlindsPosted struct {
+	Antes          []int
+	SmallBlindSeat int
+	SmallBlind     int
+	BigBlindSeat   int
+	BigBlind       int
+}
+
+// CardsDealt is the hole cards of the player in Seat.
+type CardsDealt struct {
+	Seat int
+	Hole poker.PersonalHand
+}
+
+// ActionTaken is an action taken by a player. Amount is the chips it put
+// in with the action.
+type ActionTaken struct {
+	PlayerAction
+	Amount int
+}
+
+// StreetDealt is the cards of the flop, turn or river, dealt after the
+// betting or run out once nobody can bet.
+type StreetDealt struct {
+	Street Street
+	Cards  []poker.Card
+}
+
+// BetReturned is a bet nobody called given back to the player in Seat.
+type BetReturned struct {
+	Seat   int
+	Amount int
+}
+
+// Showdown holds the hole cards and best hands of the players still in,
+// indexed by seat.
+type Showdown struct {
+	Holes []poker.PersonalHand
+	Hands []*poker.Hand
+}
+
+// PotAwarded is the Index-th pot paid out. Won holds the chips each seat
+// got from it.
+type PotAwarded struct {
+	Index int
+	Pot   Pot
+	Won   []int
+}
+
+// HandEnded closes every hand.
+type HandEnded struct {
+	Result *Result
+}
+
+func (HandStarted) event()  {}
+func (BlindsPosted) event() {}
+func (CardsDealt) event()   {}
+func (ActionTaken) event()  {}
+func (StreetDealt) event()  {}
+func (BetReturned) event()  {}
+func (Showdown) event()     {}
+func (PotAwarded) event()   {}
+func (HandEnded) event()    {}
+
+// Observer is told of the events of a hand as they happen. Events must not
+// be modified.
+type Observer interface {
+	Observe(e Event)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Chan returns an Observer sending the events to ch. The game waits for
+// ch to take each event.
+func Chan(ch chan<- Event) Observer {
+	return ObserverFunc(func(e Event) { ch <- e })
+}
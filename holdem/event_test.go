@@ -0,0 +1,92 @@
+package holdem
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+)
+
+func mustParseCards(t *testing.T, s string) []poker.Card {
+	t.Helper()
+	cards, err := poker.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestGame_Events(t *testing.T) {
+	t.Parallel()
+	var events []Event
+	cfg := Config{SmallBlind: 1, BigBlind: 2}
+	deck := stackedDeck(t, "As Kd Ah Kc", "2c 7d 9h Th 3s")
+	cards := append([]poker.Card(nil), deck.Cards...)
+	g, err := NewGame(cfg, []int{100, 100}, 1, deck,
+		ObserverFunc(func(e Event) { events = append(events, e) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Raise, Amount: 6}, Action{Kind: Fold})
+
+	want := []Event{
+		HandStarted{Config: cfg, Button: 1, Stacks: []int{100, 100}, Deck: cards},
+		BlindsPosted{Antes: []int{0, 0}, SmallBlindSeat: 1, SmallBlind: 1, BigBlindSeat: 0, BigBlind: 2},
+		CardsDealt{Seat: 0, Hole: poker.PersonalHand{Cards: mustParseCards(t, "As Ah")}},
+		CardsDealt{Seat: 1, Hole: poker.PersonalHand{Cards: mustParseCards(t, "Kd Kc")}},
+		ActionTaken{PlayerAction: PlayerAction{Seat: 1, Street: Preflop, Action: Action{Kind: Raise, Amount: 6}}, Amount: 5},
+		ActionTaken{PlayerAction: PlayerAction{Seat: 0, Street: Preflop, Action: Action{Kind: Fold}}},
+		BetReturned{Seat: 1, Amount: 4},
+		PotAwarded{Index: 0, Pot: Pot{Amount: 4, Eligible: []int{1}, Winners: []int{1}}, Won: []int{0, 4}},
+		HandEnded{Result: g.Result()},
+	}
+	if diff := cmp.Diff(events, want); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestGame_Events_Showdown(t *testing.T) {
+	t.Parallel()
+	ch := make(chan Event)
+	done := make(chan []string)
+	go func() {
+		var kinds []string
+		for e := range ch {
+			switch e := e.(type) {
+			case StreetDealt:
+				kinds = append(kinds, fmt.Sprintf("%v %v", e.Street, e.Cards))
+			default:
+				kinds = append(kinds, fmt.Sprintf("%T", e))
+			}
+		}
+		done <- kinds
+	}()
+
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2}, []int{100, 100}, 1,
+		stackedDeck(t, "As Kd Ah Kc", "2c 7d 9h Th 3s"), Chan(ch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Raise, Amount: 100}, Action{Kind: Call})
+	close(ch)
+
+	// the board is run out once both players are all in
+	want := []string{
+		"holdem.HandStarted",
+		"holdem.BlindsPosted",
+		"holdem.CardsDealt",
+		"holdem.CardsDealt",
+		"holdem.ActionTaken",
+		"holdem.ActionTaken",
+		fmt.Sprintf("flop %v", mustParseCards(t, "2c 7d 9h")),
+		fmt.Sprintf("turn %v", mustParseCards(t, "Th")),
+		fmt.Sprintf("river %v", mustParseCards(t, "3s")),
+		"holdem.Showdown",
+		"holdem.PotAwarded",
+		"holdem.HandEnded",
+	}
+	if diff := cmp.Diff(<-done, want); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
@@ -0,0 +1,587 @@
+package holdem
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yuzuy/poker"
+)
+
+type Config struct {
+	SmallBlind int
+	BigBlind   int
+	Ante       int
+	Rake       Rake
+}
+
+// Pot is a main or side pot, won by Winners among the players Eligible for
+// it. The winners share Amount less Rake and Jackpot.
+type Pot struct {
+	Amount   int
+	Rake     int
+	Jackpot  int
+	Eligible []int
+	Winners  []int
+}
+
+type Result struct {
+	// Stacks holds the chips of each seat after the hand, and Net how
+	// many it won or lost.
+	Stacks []int
+	Net    []int
+	Pots   []Pot
+	Board  poker.Board
+	// Hands holds the best hand of each player at the showdown.
+	Hands    []*poker.Hand
+	Showdown bool
+	Rake     int
+	Jackpot  int
+	// Ledger accounts for every chip put in during the hand.
+	Ledger []Entry
+}
+
+// Game is a hand of no limit Hold'em. Seats with an empty stack are not
+// dealt in.
+type Game struct {
+	cfg    Config
+	deck   *poker.Deck
+	button int
+	hole   []poker.PersonalHand
+	board  poker.Board
+	street Street
+	stacks []int
+	start  []int
+	// bets holds the chips put in on the street, and total those put in
+	// during the hand including antes.
+	bets   []int
+	total  []int
+	folded []bool
+	// acted is set once a player acts after the last bet or raise, and
+	// canRaise is cleared once it acts after the last full raise.
+	acted      []bool
+	canRaise   []bool
+	toAct      int
+	currentBet int
+	lastRaise  int
+	history    []PlayerAction
+	result     *Result
+	observers  []Observer
+}
+
+// NewGame posts the antes and blinds and deals the hole cards from deck.
+// The seat after button posts the small blind, or button itself heads up.
+// The observers are told of every event of the hand, starting with these.
+func NewGame(cfg Config, stacks []int, button int, deck *poker.Deck, observers ...Observer) (*Game, error) {
+	if cfg.SmallBlind < 0 || cfg.BigBlind <= 0 || cfg.Ante < 0 {
+		return nil, errors.New("holdem: invalid blinds")
+	}
+	if err := cfg.Rake.validate(); err != nil {
+		return nil, err
+	}
+	n := len(stacks)
+	players := 0
+	for _, s := range stacks {
+		if s < 0 {
+			return nil, fmt.Errorf("holdem: invalid stack %d", s)
+		}
+		if s > 0 {
+			players++
+		}
+	}
+	if players < 2 {
+		return nil, errors.New("holdem: need at least 2 players")
+	}
+	if button < 0 || button >= n || stacks[button] == 0 {
+		return nil, fmt.Errorf("holdem: invalid button %d", button)
+	}
+	if len(deck.Cards) < 2*players+8 {
+		return nil, errors.New("holdem: not enough cards in the deck")
+	}
+
+	g := &Game{
+		cfg:       cfg,
+		deck:      deck,
+		button:    button,
+		hole:      make([]poker.PersonalHand, n),
+		stacks:    append([]int(nil), stacks...),
+		start:     append([]int(nil), stacks...),
+		bets:      make([]int, n),
+		total:     make([]int, n),
+		folded:    make([]bool, n),
+		acted:     make([]bool, n),
+		canRaise:  make([]bool, n),
+		observers: observers,
+	}
+	for i, s := range stacks {
+		g.folded[i] = s == 0
+	}
+	g.emit(HandStarted{Config: cfg, Button: button, Stacks: append([]int(nil), stacks...), Deck: append([]poker.Card(nil), deck.Cards...)})
+
+	blinds := BlindsPosted{Antes: make([]int, n)}
+	for i := range stacks {
+		if !g.folded[i] {
+			ante := min(cfg.Ante, g.stacks[i])
+			g.stacks[i] -= ante
+			g.total[i] += ante
+			blinds.Antes[i] = ante
+		}
+	}
+	sb, bb := g.next(button), g.next(g.next(button))
+	if players == 2 {
+		sb, bb = button, g.next(button)
+	}
+	blinds.SmallBlindSeat, blinds.SmallBlind = sb, g.post(sb, cfg.SmallBlind)
+	blinds.BigBlindSeat, blinds.BigBlind = bb, g.post(bb, cfg.BigBlind)
+	g.currentBet, g.lastRaise = cfg.BigBlind, cfg.BigBlind
+	g.emit(blinds)
+
+	for round := 0; round < 2; round++ {
+		for i, seat := 0, g.next(button); i < players; i, seat = i+1, g.next(seat) {
+			g.hole[seat].Cards = append(g.hole[seat].Cards, deck.Draw())
+		}
+	}
+	for i, seat := 0, g.next(button); i < players; i, seat = i+1, g.next(seat) {
+		g.emit(CardsDealt{Seat: seat, Hole: g.Hole(seat)})
+	}
+
+	g.startRound(g.next(bb))
+	return g, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// post moves up to amount from the stack of seat to its bet and returns
+// how much it moved.
+func (g *Game) post(seat, amount int) int {
+	amount = min(amount, g.stacks[seat])
+	g.stacks[seat] -= amount
+	g.bets[seat] += amount
+	g.total[seat] += amount
+	return amount
+}
+
+func (g *Game) emit(e Event) {
+	for _, o := range g.observers {
+		o.Observe(e)
+	}
+}
+
+// deal burns a card and deals the next street to the board.
+func (g *Game) deal(street Street) {
+	g.deck.Draw()
+	cards := 1
+	if street == Flop {
+		cards = 3
+	}
+	dealt := make([]poker.Card, cards)
+	for i := range dealt {
+		dealt[i] = g.deck.Draw()
+	}
+	g.board.Cards = append(g.board.Cards, dealt...)
+	g.emit(StreetDealt{Street: street, Cards: append([]poker.Card(nil), dealt...)})
+}
+
+// next returns the seat after seat of a player dealt in.
+func (g *Game) next(seat int) int {
+	for {
+		seat = (seat + 1) % len(g.stacks)
+		if g.start[seat] > 0 {
+			return seat
+		}
+	}
+}
+
+// canAct reports whether seat still has decisions to make this hand.
+func (g *Game) canAct(seat int) bool {
+	return !g.folded[seat] && g.stacks[seat] > 0
+}
+
+func (g *Game) startRound(first int) {
+	for i := range g.acted {
+		g.acted[i] = false
+		g.canRaise[i] = true
+	}
+	g.toAct = g.after(first - 1)
+	if g.toAct < 0 {
+		g.endRound()
+	}
+}
+
+// after returns the next seat after seat that has to act, or -1 when the
+// betting round is over.
+func (g *Game) after(seat int) int {
+	live, waiting := 0, -1
+	for i := 0; i < len(g.stacks); i++ {
+		s := (seat + 1 + i + len(g.stacks)) % len(g.stacks)
+		if g.folded[s] {
+			continue
+		}
+		live++
+		if g.canAct(s) && waiting < 0 && (!g.acted[s] || g.bets[s] < g.currentBet) {
+			waiting = s
+		}
+	}
+	if live < 2 {
+		return -1
+	}
+	if waiting >= 0 && !g.acted[waiting] && g.bets[waiting] >= g.currentBet && g.actors() < 2 {
+		// nobody is left to bet against
+		return -1
+	}
+	return waiting
+}
+
+// actors returns the number of players who can still bet.
+func (g *Game) actors() int {
+	n := 0
+	for i := range g.stacks {
+		if g.canAct(i) {
+			n++
+		}
+	}
+	return n
+}
+
+func (g *Game) live() int {
+	n := 0
+	for _, f := range g.folded {
+		if !f {
+			n++
+		}
+	}
+	return n
+}
+
+func (g *Game) endRound() {
+	for i := range g.bets {
+		g.bets[i] = 0
+	}
+	g.currentBet, g.lastRaise = 0, g.cfg.BigBlind
+	if g.live() < 2 {
+		g.settle()
+		return
+	}
+	if g.street == River {
+		g.settle()
+		return
+	}
+	g.street++
+	g.deal(g.street)
+	g.startRound(g.next(g.button))
+}
+
+func (g *Game) Done() bool {
+	return g.result != nil
+}
+
+// ToAct returns the seat to act, or -1 once the hand is over.
+func (g *Game) ToAct() int {
+	if g.Done() {
+		return -1
+	}
+	return g.toAct
+}
+
+func (g *Game) Street() Street {
+	return g.street
+}
+
+func (g *Game) Board() poker.Board {
+	return poker.Board{Cards: append([]poker.Card(nil), g.board.Cards...)}
+}
+
+func (g *Game) Hole(seat int) poker.PersonalHand {
+	return poker.PersonalHand{Cards: append([]poker.Card(nil), g.hole[seat].Cards...)}
+}
+
+func (g *Game) History() []PlayerAction {
+	return append([]PlayerAction(nil), g.history...)
+}
+
+func (g *Game) pot() int {
+	pot := 0
+	for _, t := range g.total {
+		pot += t
+	}
+	return pot
+}
+
+// View returns what the player in seat knows.
+func (g *Game) View(seat int) *View {
+	v := &View{
+		Seat:     seat,
+		Hole:     g.Hole(seat),
+		Board:    g.Board(),
+		Street:   g.street,
+		Button:   g.button,
+		Pot:      g.pot(),
+		Stacks:   append([]int(nil), g.stacks...),
+		Bets:     append([]int(nil), g.bets...),
+		Folded:   append([]bool(nil), g.folded...),
+		ToCall:   min(g.currentBet-g.bets[seat], g.stacks[seat]),
+		BigBlind: g.cfg.BigBlind,
+		History:  g.History(),
+	}
+	if v.ToCall < 0 {
+		v.ToCall = 0
+	}
+	if g.canRaise[seat] && g.stacks[seat] > g.currentBet-g.bets[seat] {
+		v.MinRaise = g.currentBet + g.lastRaise
+	}
+	return v
+}
+
+// Act applies the action of the player to act.
+func (g *Game) Act(a Action) error {
+	if g.Done() {
+		return errors.New("holdem: the hand is over")
+	}
+	seat := g.toAct
+	before := g.total[seat]
+	toCall := g.currentBet - g.bets[seat]
+	allIn := g.bets[seat] + g.stacks[seat]
+	switch a.Kind {
+	case Fold:
+		g.folded[seat] = true
+	case Check:
+		if toCall > 0 {
+			return errors.New("holdem: cannot check facing a bet")
+		}
+	case Call:
+		if toCall <= 0 {
+			return errors.New("holdem: nothing to call")
+		}
+		g.post(seat, toCall)
+	case Bet, Raise:
+		if a.Kind == Bet && g.currentBet > 0 {
+			return errors.New("holdem: cannot bet facing a bet, raise instead")
+		}
+		if a.Kind == Raise && g.currentBet == 0 {
+			return errors.New("holdem: nothing to raise, bet instead")
+		}
+		if !g.canRaise[seat] {
+			return errors.New("holdem: the betting is not reopened")
+		}
+		if a.Amount > allIn {
+			return fmt.Errorf("holdem: %s is more than the stack", a)
+		}
+		if a.Amount <= g.currentBet || a.Amount < g.currentBet+g.lastRaise && a.Amount < allIn {
+			return fmt.Errorf("holdem: %s is less than the minimum of %d", a, g.currentBet+g.lastRaise)
+		}
+		if raise := a.Amount - g.currentBet; raise >= g.lastRaise {
+			g.lastRaise = raise
+			for i := range g.canRaise {
+				g.canRaise[i] = true
+			}
+		}
+		for i := range g.acted {
+			g.acted[i] = false
+		}
+		g.post(seat, a.Amount-g.bets[seat])
+		g.currentBet = a.Amount
+	default:
+		return fmt.Errorf("holdem: unknown action %v", a.Kind)
+	}
+	g.acted[seat] = true
+	g.canRaise[seat] = false
+	pa := PlayerAction{Seat: seat, Street: g.street, Action: a}
+	g.history = append(g.history, pa)
+	g.emit(ActionTaken{PlayerAction: pa, Amount: g.total[seat] - before})
+
+	if g.toAct = g.after(seat); g.toAct < 0 {
+		g.endRound()
+	}
+	return nil
+}
+
+// Play asks agents, indexed by seat, for their actions until the hand is
+// over. Illegal actions are taken as a check or a fold.
+func (g *Game) Play(agents []Agent) *Result {
+	for !g.Done() {
+		v := g.View(g.toAct)
+		if err := g.Act(agents[g.toAct].Act(v)); err != nil {
+			_ = g.Act(v.CheckOrFold())
+		}
+	}
+	return g.Result()
+}
+
+// Result returns the outcome of the hand, or nil until it is over.
+func (g *Game) Result() *Result {
+	return g.result
+}
+
+// uncalled returns the seat whose bet nobody called and how much of it, or
+// -1 and 0.
+func (g *Game) uncalled() (int, int) {
+	top, second := -1, 0
+	for i, t := range g.total {
+		switch {
+		case top < 0 || t > g.total[top]:
+			if top >= 0 {
+				second = g.total[top]
+			}
+			top = i
+		case t > second:
+			second = t
+		}
+	}
+	if g.total[top] == second {
+		return -1, 0
+	}
+	return top, g.total[top] - second
+}
+
+// pots splits the chips put in into the main and side pots, leaving out
+// the bet nobody called.
+func (g *Game) pots() []Pot {
+	var pots []Pot
+	left := append([]int(nil), g.total...)
+	if seat, amount := g.uncalled(); seat >= 0 {
+		left[seat] -= amount
+	}
+	for {
+		level := 0
+		for i, t := range left {
+			if !g.folded[i] && t > 0 && (level == 0 || t < level) {
+				level = t
+			}
+		}
+		if level == 0 {
+			break
+		}
+		var p Pot
+		for i := range left {
+			take := min(left[i], level)
+			p.Amount += take
+			left[i] -= take
+			if !g.folded[i] && take == level {
+				p.Eligible = append(p.Eligible, i)
+			}
+		}
+		pots = append(pots, p)
+	}
+	// chips of folded players above what anyone still in put in
+	for _, t := range left {
+		if t > 0 && len(pots) > 0 {
+			pots[len(pots)-1].Amount += t
+		}
+	}
+	return pots
+}
+
+func (g *Game) settle() {
+	for street := g.street + 1; street <= River && g.live() > 1; street++ {
+		g.deal(street)
+	}
+
+	r := &Result{Board: g.Board(), Hands: make([]*poker.Hand, len(g.stacks)), Showdown: g.live() > 1}
+	if r.Showdown {
+		for i := range g.stacks {
+			if !g.folded[i] {
+				cards := append(g.Hole(i).Cards, g.board.Cards...)
+				r.Hands[i], _ = poker.BestHand(cards)
+			}
+		}
+	}
+	for i, t := range g.total {
+		if t > 0 {
+			r.Ledger = append(r.Ledger, Entry{Kind: Contributed, Seat: i, Pot: -1, Amount: t})
+		}
+	}
+	if seat, amount := g.uncalled(); seat >= 0 {
+		g.stacks[seat] += amount
+		r.Ledger = append(r.Ledger, Entry{Kind: Returned, Seat: seat, Pot: -1, Amount: amount})
+		g.emit(BetReturned{Seat: seat, Amount: amount})
+	}
+	if r.Showdown {
+		sd := Showdown{Holes: make([]poker.PersonalHand, len(g.stacks)), Hands: append([]*poker.Hand(nil), r.Hands...)}
+		for i := range g.stacks {
+			if !g.folded[i] {
+				sd.Holes[i] = g.Hole(i)
+			}
+		}
+		g.emit(sd)
+	}
+
+	pots := g.pots()
+	amounts := make([]int, len(pots))
+	for i, p := range pots {
+		amounts[i] = p.Amount
+	}
+	players := 0
+	for _, s := range g.start {
+		if s > 0 {
+			players++
+		}
+	}
+	rake, jackpot := g.cfg.Rake.take(amounts, players, g.street > Preflop)
+
+	for pi, p := range pots {
+		p.Rake, p.Jackpot = rake[pi], jackpot[pi]
+		r.Rake += p.Rake
+		r.Jackpot += p.Jackpot
+		if p.Rake > 0 {
+			r.Ledger = append(r.Ledger, Entry{Kind: Raked, Seat: -1, Pot: pi, Amount: p.Rake})
+		}
+		if p.Jackpot > 0 {
+			r.Ledger = append(r.Ledger, Entry{Kind: Dropped, Seat: -1, Pot: pi, Amount: p.Jackpot})
+		}
+		for _, i := range p.Eligible {
+			if len(p.Winners) == 0 {
+				p.Winners = []int{i}
+				continue
+			}
+			switch r.Hands[i].Compare(r.Hands[p.Winners[0]]) {
+			case poker.Win:
+				p.Winners = []int{i}
+			case poker.Draw:
+				p.Winners = append(p.Winners, i)
+			}
+		}
+		won := g.award(p)
+		for seat, amount := range won {
+			if amount > 0 {
+				r.Ledger = append(r.Ledger, Entry{Kind: Won, Seat: seat, Pot: pi, Amount: amount})
+			}
+		}
+		r.Pots = append(r.Pots, p)
+		g.emit(PotAwarded{Index: pi, Pot: p, Won: won})
+	}
+	r.Stacks = append([]int(nil), g.stacks...)
+	r.Net = make([]int, len(g.stacks))
+	for i := range r.Net {
+		r.Net[i] = g.stacks[i] - g.start[i]
+	}
+	g.toAct = -1
+	g.result = r
+	g.emit(HandEnded{Result: r})
+}
+
+// award splits p between its winners and returns what each seat won. The
+// odd chips go to the winners closest to the left of the button.
+func (g *Game) award(p Pot) []int {
+	won := make([]int, len(g.stacks))
+	amount := p.Amount - p.Rake - p.Jackpot
+	share, odd := amount/len(p.Winners), amount%len(p.Winners)
+	for _, w := range p.Winners {
+		won[w] += share
+	}
+	for seat := g.next(g.button); odd > 0; seat = g.next(seat) {
+		for _, w := range p.Winners {
+			if w == seat && odd > 0 {
+				won[w]++
+				odd--
+			}
+		}
+	}
+	for seat, w := range won {
+		g.stacks[seat] += w
+	}
+	return won
+}
@@ -0,0 +1,259 @@
+package holdem
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+)
+
+// stackedDeck returns a deck dealing cards in order, with a burn card
+// before each street.
+func stackedDeck(t *testing.T, holes, board string) *poker.Deck {
+	t.Helper()
+	cards, err := poker.ParseCards(holes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := poker.ParseCards(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	burn := func() poker.Card {
+		for _, c := range poker.FullCardSet.Cards() {
+			if !poker.NewCardSet(cards...).Contains(c) && !poker.NewCardSet(b...).Contains(c) {
+				cards = append(cards, c)
+				return c
+			}
+		}
+		t.Fatal("no card left to burn")
+		return poker.Card{}
+	}
+	burn()
+	cards = append(cards, b[:3]...)
+	burn()
+	cards = append(cards, b[3])
+	burn()
+	cards = append(cards, b[4])
+	return poker.NewDeck(cards...)
+}
+
+func mustAct(t *testing.T, g *Game, actions ...Action) {
+	t.Helper()
+	for _, a := range actions {
+		if err := g.Act(a); err != nil {
+			t.Fatalf("Act(%v) by seat %d error: %v", a, g.ToAct(), err)
+		}
+	}
+}
+
+func TestNewGame(t *testing.T) {
+	t.Parallel()
+	// seats 1 and 2 get As Ks and Qh Qd, dealt one card at a time from the
+	// seat after the button
+	g, err := NewGame(Config{SmallBlind: 5, BigBlind: 10, Ante: 1}, []int{100, 100, 0, 100}, 0,
+		stackedDeck(t, "As Qh 7c Ks Qd 2d", "2c 3c 4c 5d 9s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.ToAct(); got != 0 {
+		t.Errorf("want is seat 0 to act, but got %d", got)
+	}
+	want := &View{
+		Seat:     0,
+		Hole:     poker.PersonalHand{Cards: []poker.Card{{Suit: poker.Club, Rank: 7}, {Suit: poker.Diamond, Rank: 2}}},
+		Board:    poker.Board{},
+		Button:   0,
+		Pot:      18,
+		Stacks:   []int{99, 94, 0, 89},
+		Bets:     []int{0, 5, 0, 10},
+		Folded:   []bool{false, false, true, false},
+		ToCall:   10,
+		MinRaise: 20,
+		BigBlind: 10,
+	}
+	if diff := cmp.Diff(g.View(0), want); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if diff := cmp.Diff(g.Hole(1).Cards, []poker.Card{{Suit: poker.Spade, Rank: poker.Ace}, {Suit: poker.Spade, Rank: poker.King}}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestNewGame_Error(t *testing.T) {
+	t.Parallel()
+	deck := func() *poker.Deck { return poker.FullCardSet.Deck() }
+	tests := []struct {
+		name   string
+		cfg    Config
+		stacks []int
+		button int
+		deck   *poker.Deck
+	}{
+		{name: "no big blind", cfg: Config{SmallBlind: 1}, stacks: []int{10, 10}, deck: deck()},
+		{name: "one player", cfg: Config{SmallBlind: 1, BigBlind: 2}, stacks: []int{10, 0}, deck: deck()},
+		{name: "negative stack", cfg: Config{SmallBlind: 1, BigBlind: 2}, stacks: []int{10, -1, 10}, deck: deck()},
+		{name: "empty button", cfg: Config{SmallBlind: 1, BigBlind: 2}, stacks: []int{10, 0, 10}, button: 1, deck: deck()},
+		{name: "short deck", cfg: Config{SmallBlind: 1, BigBlind: 2}, stacks: []int{10, 10}, deck: poker.NewDeck(deck().Cards[:11]...)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := NewGame(tt.cfg, tt.stacks, tt.button, tt.deck); err == nil {
+				t.Error("want error but got nil")
+			}
+		})
+	}
+}
+
+func TestGame_HeadsUp(t *testing.T) {
+	t.Parallel()
+	// the button posts the small blind and acts first before the flop
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2}, []int{100, 100}, 1,
+		stackedDeck(t, "As Kd Ah Kc", "2c 7d 9h Th 3s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.ToAct() != 1 {
+		t.Fatalf("want is seat 1 to act, but got %d", g.ToAct())
+	}
+	mustAct(t, g, Action{Kind: Call}, Action{Kind: Check})
+	if g.Street() != Flop || g.ToAct() != 0 {
+		t.Fatalf("want is seat 0 to act on the flop, but got seat %d on the %v", g.ToAct(), g.Street())
+	}
+	mustAct(t, g, Action{Kind: Bet, Amount: 4}, Action{Kind: Raise, Amount: 12}, Action{Kind: Call})
+	mustAct(t, g, Action{Kind: Check}, Action{Kind: Check})
+	mustAct(t, g, Action{Kind: Check}, Action{Kind: Bet, Amount: 20}, Action{Kind: Fold})
+	if !g.Done() {
+		t.Fatal("the hand is not over")
+	}
+	r := g.Result()
+	if diff := cmp.Diff(r.Net, []int{-14, 14}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if r.Showdown {
+		t.Error("Showdown = true, want false")
+	}
+	// the last bet is returned rather than won
+	wantPots := []Pot{{Amount: 28, Eligible: []int{1}, Winners: []int{1}}}
+	if diff := cmp.Diff(r.Pots, wantPots); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if len(g.History()) != 10 {
+		t.Errorf("want is 10 actions, but got %d", len(g.History()))
+	}
+}
+
+func TestGame_SidePots(t *testing.T) {
+	t.Parallel()
+	// seat 1 has the best hand but only covers the main pot, seat 2 beats
+	// seat 0 for the side pot and seat 0 gets back what nobody called
+	g, err := NewGame(Config{SmallBlind: 5, BigBlind: 10}, []int{200, 50, 100}, 0,
+		stackedDeck(t, "As Qs 2h Ad Qd 2d", "Ah Kc 7d 4s 3c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Raise, Amount: 200}, Action{Kind: Call}, Action{Kind: Call})
+	if !g.Done() {
+		t.Fatal("the hand is not over")
+	}
+	r := g.Result()
+	want := []Pot{
+		{Amount: 150, Eligible: []int{0, 1, 2}, Winners: []int{1}},
+		{Amount: 100, Eligible: []int{0, 2}, Winners: []int{2}},
+	}
+	if diff := cmp.Diff(r.Pots, want); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if diff := cmp.Diff(r.Stacks, []int{100, 150, 100}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if !r.Showdown || len(r.Board.Cards) != 5 {
+		t.Errorf("want is a showdown on 5 cards, but got showdown %v with board %v", r.Showdown, r.Board.Cards)
+	}
+}
+
+func TestGame_SplitPot(t *testing.T) {
+	t.Parallel()
+	// both play the board; the odd chip goes left of the button
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2, Ante: 1}, []int{50, 50, 51}, 0,
+		stackedDeck(t, "2c 3c 4c 2d 3d 4d", "As Ks Qs Js Ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Fold}, Action{Kind: Call}, Action{Kind: Check})
+	for !g.Done() {
+		mustAct(t, g, Action{Kind: Check})
+	}
+	r := g.Result()
+	if diff := cmp.Diff(r.Pots, []Pot{{Amount: 7, Eligible: []int{1, 2}, Winners: []int{1, 2}}}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if diff := cmp.Diff(r.Net, []int{-1, 1, 0}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestGame_Act_Error(t *testing.T) {
+	t.Parallel()
+	newGame := func() *Game {
+		g, err := NewGame(Config{SmallBlind: 5, BigBlind: 10}, []int{100, 100, 45}, 0, poker.FullCardSet.Deck())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+	tests := []struct {
+		name    string
+		before  []Action
+		action  Action
+		wantErr string
+	}{
+		{name: "check facing a bet", action: Action{Kind: Check}, wantErr: "cannot check"},
+		{name: "bet preflop", action: Action{Kind: Bet, Amount: 30}, wantErr: "raise instead"},
+		{name: "raise below the minimum", action: Action{Kind: Raise, Amount: 15}, wantErr: "minimum"},
+		{name: "raise above the stack", action: Action{Kind: Raise, Amount: 101}, wantErr: "more than the stack"},
+		{
+			// seat 2 going all in for 45 is not a full raise over 30
+			name:    "incomplete raise does not reopen",
+			before:  []Action{{Kind: Raise, Amount: 30}, {Kind: Call}, {Kind: Raise, Amount: 45}},
+			action:  Action{Kind: Raise, Amount: 90},
+			wantErr: "not reopened",
+		},
+		{
+			name:    "call without a bet",
+			before:  []Action{{Kind: Call}, {Kind: Call}, {Kind: Check}, {Kind: Check}},
+			action:  Action{Kind: Call},
+			wantErr: "nothing to call",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := newGame()
+			mustAct(t, g, tt.before...)
+			err := g.Act(tt.action)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("want is %q, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGame_Play(t *testing.T) {
+	t.Parallel()
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2}, []int{100, 100}, 0, poker.FullCardSet.Deck())
+	if err != nil {
+		t.Fatal(err)
+	}
+	shove := AgentFunc(func(v *View) Action { return v.BetTo(v.MaxBet()) })
+	// betting more than the stack is illegal and taken as a fold
+	tooMuch := AgentFunc(func(v *View) Action { return Action{Kind: Raise, Amount: 1000} })
+	r := g.Play([]Agent{tooMuch, shove})
+	if diff := cmp.Diff(r.Net, []int{-1, 1}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
@@ -0,0 +1,173 @@
+// Package holdem defines how players of no limit Texas Hold'em see a hand
+// and act in it.
+package holdem
+
+import (
+	"fmt"
+
+	"github.com/yuzuy/poker"
+)
+
+type ActionKind int
+
+const (
+	Fold ActionKind = iota + 1
+	Check
+	Call
+	Bet
+	Raise
+)
+
+func (k ActionKind) String() string {
+	switch k {
+	case Fold:
+		return "fold"
+	case Check:
+		return "check"
+	case Call:
+		return "call"
+	case Bet:
+		return "bet"
+	case Raise:
+		return "raise"
+	default:
+		return "unknown"
+	}
+}
+
+// Action is a move of a player. Amount is the total the player has bet on
+// the street after a bet or raise, and zero otherwise.
+type Action struct {
+	Kind   ActionKind
+	Amount int
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case Bet:
+		return fmt.Sprintf("bet %d", a.Amount)
+	case Raise:
+		return fmt.Sprintf("raise to %d", a.Amount)
+	default:
+		return a.Kind.String()
+	}
+}
+
+type Street int
+
+const (
+	Preflop Street = iota
+	Flop
+	Turn
+	River
+)
+
+func (s Street) String() string {
+	switch s {
+	case Preflop:
+		return "preflop"
+	case Flop:
+		return "flop"
+	case Turn:
+		return "turn"
+	case River:
+		return "river"
+	default:
+		return "unknown"
+	}
+}
+
+// PlayerAction is an action taken by the player in Seat.
+type PlayerAction struct {
+	Seat   int
+	Street Street
+	Action Action
+}
+
+// View is what the player in Seat knows when it is its turn to act.
+// Stacks, Bets and Folded are indexed by seat.
+type View struct {
+	Seat   int
+	Hole   poker.PersonalHand
+	Board  poker.Board
+	Street Street
+	Button int
+	// Pot holds every chip put in so far, including the bets of the
+	// street.
+	Pot int
+	// Stacks holds the chips each player has behind.
+	Stacks []int
+	// Bets holds the chips each player has put in on the street.
+	Bets   []int
+	Folded []bool
+	ToCall int
+	// MinRaise is the smallest total a bet or raise can make the bet, or
+	// zero when the player may not raise.
+	MinRaise int
+	BigBlind int
+	History  []PlayerAction
+}
+
+// MaxBet returns the total bet of the player when it goes all in.
+func (v *View) MaxBet() int {
+	return v.Bets[v.Seat] + v.Stacks[v.Seat]
+}
+
+// Active returns the number of players who have not folded.
+func (v *View) Active() int {
+	n := 0
+	for _, f := range v.Folded {
+		if !f {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckOrCall returns a check, or a call when facing a bet.
+func (v *View) CheckOrCall() Action {
+	if v.ToCall == 0 {
+		return Action{Kind: Check}
+	}
+	return Action{Kind: Call}
+}
+
+// CheckOrFold returns a check, or a fold when facing a bet.
+func (v *View) CheckOrFold() Action {
+	if v.ToCall == 0 {
+		return Action{Kind: Check}
+	}
+	return Action{Kind: Fold}
+}
+
+// BetTo returns a bet or raise making the total bet of the player amount,
+// kept between the minimum raise and all in. It calls when the player
+// cannot raise.
+func (v *View) BetTo(amount int) Action {
+	current := v.Bets[v.Seat] + v.ToCall
+	if v.MinRaise == 0 || v.MaxBet() <= current {
+		return v.CheckOrCall()
+	}
+	if amount < v.MinRaise {
+		amount = v.MinRaise
+	}
+	if amount > v.MaxBet() {
+		amount = v.MaxBet()
+	}
+	if current == 0 {
+		return Action{Kind: Bet, Amount: amount}
+	}
+	return Action{Kind: Raise, Amount: amount}
+}
+
+// Agent decides the actions of a player.
+type Agent interface {
+	Act(v *View) Action
+}
+
+// AgentFunc adapts a function to an Agent.
+type AgentFunc func(v *View) Action
+
+func (f AgentFunc) Act(v *View) Action {
+	return f(v)
+}
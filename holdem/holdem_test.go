@@ -0,0 +1,117 @@
+package holdem
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestView_BetTo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		v      *View
+		amount int
+		want   Action
+	}{
+		{
+			name:   "bet",
+			v:      &View{Seat: 0, Stacks: []int{100, 100}, Bets: []int{0, 0}, MinRaise: 2},
+			amount: 10,
+			want:   Action{Kind: Bet, Amount: 10},
+		},
+		{
+			name:   "raise the blinds",
+			v:      &View{Seat: 0, Stacks: []int{99, 98}, Bets: []int{1, 2}, ToCall: 1, MinRaise: 4},
+			amount: 6,
+			want:   Action{Kind: Raise, Amount: 6},
+		},
+		{
+			name:   "below the minimum raise",
+			v:      &View{Seat: 1, Stacks: []int{90, 100}, Bets: []int{10, 0}, ToCall: 10, MinRaise: 20},
+			amount: 15,
+			want:   Action{Kind: Raise, Amount: 20},
+		},
+		{
+			name:   "above the stack",
+			v:      &View{Seat: 1, Stacks: []int{90, 50}, Bets: []int{10, 0}, ToCall: 10, MinRaise: 20},
+			amount: 500,
+			want:   Action{Kind: Raise, Amount: 50},
+		},
+		{
+			name:   "raising is closed",
+			v:      &View{Seat: 1, Stacks: []int{90, 100}, Bets: []int{10, 0}, ToCall: 10},
+			amount: 30,
+			want:   Action{Kind: Call},
+		},
+		{
+			name:   "short stack calls",
+			v:      &View{Seat: 1, Stacks: []int{90, 8}, Bets: []int{10, 0}, ToCall: 10, MinRaise: 20},
+			amount: 30,
+			want:   Action{Kind: Call},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.v.BetTo(tt.amount), tt.want); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestView_CheckOrCall(t *testing.T) {
+	t.Parallel()
+	v := &View{}
+	if got := v.CheckOrCall(); got.Kind != Check {
+		t.Errorf("want is check, but got %v", got)
+	}
+	if got := v.CheckOrFold(); got.Kind != Check {
+		t.Errorf("want is check, but got %v", got)
+	}
+	v.ToCall = 5
+	if got := v.CheckOrCall(); got.Kind != Call {
+		t.Errorf("want is call, but got %v", got)
+	}
+	if got := v.CheckOrFold(); got.Kind != Fold {
+		t.Errorf("want is fold, but got %v", got)
+	}
+}
+
+func TestView_Active(t *testing.T) {
+	t.Parallel()
+	v := &View{Folded: []bool{true, false, false, true}}
+	if got := v.Active(); got != 2 {
+		t.Errorf("want is 2 active players, but got %d", got)
+	}
+}
+
+func TestAction_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a    Action
+		want string
+	}{
+		{a: Action{Kind: Fold}, want: "fold"},
+		{a: Action{Kind: Check}, want: "check"},
+		{a: Action{Kind: Call}, want: "call"},
+		{a: Action{Kind: Bet, Amount: 20}, want: "bet 20"},
+		{a: Action{Kind: Raise, Amount: 60}, want: "raise to 60"},
+		{a: Action{}, want: "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("want is %s, but got %s", tt.want, got)
+		}
+	}
+}
+
+func TestAgentFunc(t *testing.T) {
+	t.Parallel()
+	var a Agent = AgentFunc(func(v *View) Action { return v.CheckOrFold() })
+	if got := a.Act(&View{ToCall: 1}); got.Kind != Fold {
+		t.Errorf("want is fold, but got %v", got)
+	}
+}
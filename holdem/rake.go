@@ -0,0 +1,132 @@
+package holdem
+
+import (
+	"errors"
+	"math"
+)
+
+// Rake is what the house takes from the pots of a hand.
+type Rake struct {
+	// Percent is the share of the pots taken, up to Cap chips a hand when
+	// Cap is set.
+	Percent float64
+	Cap     int
+	// PlayerCaps overrides Cap by the number of players dealt in.
+	PlayerCaps map[int]int
+	// NoFlopNoDrop takes nothing from hands over before the flop.
+	NoFlopNoDrop bool
+	// JackpotDrop is taken for the bad beat jackpot from hands whose pots
+	// reach JackpotMinPot.
+	JackpotDrop   int
+	JackpotMinPot int
+}
+
+func (r Rake) validate() error {
+	if r.Percent < 0 || r.Percent > 1 || math.IsNaN(r.Percent) {
+		return errors.New("holdem: rake percent must be between 0 and 1")
+	}
+	if r.Cap < 0 || r.JackpotDrop < 0 || r.JackpotMinPot < 0 {
+		return errors.New("holdem: invalid rake cap or jackpot drop")
+	}
+	for _, c := range r.PlayerCaps {
+		if c < 0 {
+			return errors.New("holdem: invalid rake cap")
+		}
+	}
+	return nil
+}
+
+// take returns the rake and jackpot drop taken from each pot.
+func (r Rake) take(pots []int, players int, flop bool) (rake, jackpot []int) {
+	rake, jackpot = make([]int, len(pots)), make([]int, len(pots))
+	total := 0
+	for _, p := range pots {
+		total += p
+	}
+	if total == 0 || r.NoFlopNoDrop && !flop {
+		return rake, jackpot
+	}
+
+	// in basis points so that 0.29 of 100 is not truncated to 28
+	bp := int(math.Round(r.Percent * 10000))
+	amount := total * bp / 10000
+	limit, ok := r.PlayerCaps[players]
+	if !ok {
+		limit = r.Cap
+	}
+	if limit > 0 && amount > limit {
+		amount = limit
+	}
+	spread(rake, pots, amount)
+
+	if r.JackpotDrop > 0 && total >= r.JackpotMinPot {
+		left := make([]int, len(pots))
+		for i, p := range pots {
+			left[i] = p - rake[i]
+		}
+		spread(jackpot, left, min(r.JackpotDrop, total-amount))
+	}
+	return rake, jackpot
+}
+
+// spread adds amount to taken in proportion to pots, the chips rounding
+// leaves going to the main pot first.
+func spread(taken, pots []int, amount int) {
+	total := 0
+	for _, p := range pots {
+		total += p
+	}
+	if total == 0 {
+		return
+	}
+	left := amount
+	for i, p := range pots {
+		share := amount * p / total
+		taken[i] += share
+		left -= share
+	}
+	for i := 0; left > 0; i = (i + 1) % len(pots) {
+		if taken[i] < pots[i] {
+			taken[i]++
+			left--
+		}
+	}
+}
+
+type EntryKind int
+
+const (
+	// Contributed is what a seat put in during the hand.
+	Contributed EntryKind = iota + 1
+	// Returned is a bet nobody called given back to its seat.
+	Returned
+	Raked
+	Dropped
+	Won
+)
+
+func (k EntryKind) String() string {
+	switch k {
+	case Contributed:
+		return "contributed"
+	case Returned:
+		return "returned"
+	case Raked:
+		return "raked"
+	case Dropped:
+		return "dropped"
+	case Won:
+		return "won"
+	default:
+		return "unknown"
+	}
+}
+
+// Entry is a movement of chips in the settlement of a hand. Seat is -1 for
+// the rake and jackpot, and Pot is -1 outside the pots.
+type Entry struct {
+	Kind   EntryKind
+	Seat   int
+	Pot    int
+	Amount int
+}
@@ -0,0 +1,181 @@
+package holdem
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRake_take(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		r           Rake
+		pots        []int
+		players     int
+		flop        bool
+		wantRake    []int
+		wantJackpot []int
+	}{
+		{
+			name:        "percent",
+			r:           Rake{Percent: 0.05},
+			pots:        []int{100},
+			players:     6,
+			flop:        true,
+			wantRake:    []int{5},
+			wantJackpot: []int{0},
+		},
+		{
+			name:        "percent not exact in float",
+			r:           Rake{Percent: 0.29},
+			pots:        []int{100},
+			players:     6,
+			flop:        true,
+			wantRake:    []int{29},
+			wantJackpot: []int{0},
+		},
+		{
+			name:        "cap",
+			r:           Rake{Percent: 0.05, Cap: 3},
+			pots:        []int{100},
+			players:     6,
+			flop:        true,
+			wantRake:    []int{3},
+			wantJackpot: []int{0},
+		},
+		{
+			name:        "cap by players",
+			r:           Rake{Percent: 0.05, Cap: 3, PlayerCaps: map[int]int{2: 1, 3: 2}},
+			pots:        []int{100},
+			players:     2,
+			flop:        true,
+			wantRake:    []int{1},
+			wantJackpot: []int{0},
+		},
+		{
+			name:        "no flop no drop",
+			r:           Rake{Percent: 0.05, NoFlopNoDrop: true, JackpotDrop: 1},
+			pots:        []int{100},
+			players:     6,
+			wantRake:    []int{0},
+			wantJackpot: []int{0},
+		},
+		{
+			name:        "raked before the flop",
+			r:           Rake{Percent: 0.05},
+			pots:        []int{100},
+			players:     6,
+			wantRake:    []int{5},
+			wantJackpot: []int{0},
+		},
+		{
+			name:        "side pots pay their share",
+			r:           Rake{Percent: 0.1, Cap: 20},
+			pots:        []int{150, 100, 50},
+			players:     3,
+			flop:        true,
+			wantRake:    []int{11, 6, 3},
+			wantJackpot: []int{0, 0, 0},
+		},
+		{
+			name:        "jackpot drop",
+			r:           Rake{Percent: 0.05, Cap: 5, JackpotDrop: 2, JackpotMinPot: 50},
+			pots:        []int{120, 80},
+			players:     3,
+			flop:        true,
+			wantRake:    []int{3, 2},
+			wantJackpot: []int{2, 0},
+		},
+		{
+			name:        "pot too small for the jackpot",
+			r:           Rake{Percent: 0.05, JackpotDrop: 2, JackpotMinPot: 50},
+			pots:        []int{40},
+			players:     3,
+			flop:        true,
+			wantRake:    []int{2},
+			wantJackpot: []int{0},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rake, jackpot := tt.r.take(tt.pots, tt.players, tt.flop)
+			if diff := cmp.Diff(rake, tt.wantRake); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+			if diff := cmp.Diff(jackpot, tt.wantJackpot); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGame_Rake(t *testing.T) {
+	t.Parallel()
+	rake := Rake{Percent: 0.1, Cap: 15, JackpotDrop: 3, JackpotMinPot: 100}
+	g, err := NewGame(Config{SmallBlind: 5, BigBlind: 10, Rake: rake}, []int{200, 50, 100}, 0,
+		stackedDeck(t, "As Qs 2h Ad Qd 2d", "Ah Kc 7d 4s 3c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Raise, Amount: 200}, Action{Kind: Call}, Action{Kind: Call})
+	r := g.Result()
+
+	// 10% of the 250 called is capped at 15 and split 9 and 6 between the
+	// pots, then the jackpot takes 3
+	wantPots := []Pot{
+		{Amount: 150, Rake: 9, Jackpot: 2, Eligible: []int{0, 1, 2}, Winners: []int{1}},
+		{Amount: 100, Rake: 6, Jackpot: 1, Eligible: []int{0, 2}, Winners: []int{2}},
+	}
+	if diff := cmp.Diff(r.Pots, wantPots); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if r.Rake != 15 || r.Jackpot != 3 {
+		t.Errorf("want is rake 15 and jackpot 3, but got %d and %d", r.Rake, r.Jackpot)
+	}
+	wantLedger := []Entry{
+		{Kind: Contributed, Seat: 0, Pot: -1, Amount: 200},
+		{Kind: Contributed, Seat: 1, Pot: -1, Amount: 50},
+		{Kind: Contributed, Seat: 2, Pot: -1, Amount: 100},
+		{Kind: Returned, Seat: 0, Pot: -1, Amount: 100},
+		{Kind: Raked, Seat: -1, Pot: 0, Amount: 9},
+		{Kind: Dropped, Seat: -1, Pot: 0, Amount: 2},
+		{Kind: Won, Seat: 1, Pot: 0, Amount: 139},
+		{Kind: Raked, Seat: -1, Pot: 1, Amount: 6},
+		{Kind: Dropped, Seat: -1, Pot: 1, Amount: 1},
+		{Kind: Won, Seat: 2, Pot: 1, Amount: 93},
+	}
+	if diff := cmp.Diff(r.Ledger, wantLedger); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+
+	in, out := 0, 0
+	for _, e := range r.Ledger {
+		if e.Kind == Contributed {
+			in += e.Amount
+		} else {
+			out += e.Amount
+		}
+	}
+	if in != out {
+		t.Errorf("%d chips put in but %d accounted for", in, out)
+	}
+	net := 0
+	for _, n := range r.Net {
+		net += n
+	}
+	if net != -18 {
+		t.Errorf("want is 18 lost in total, but got %d", -net)
+	}
+}
+
+func TestNewGame_InvalidRake(t *testing.T) {
+	t.Parallel()
+	for _, r := range []Rake{{Percent: -0.1}, {Percent: 1.5}, {Cap: -1}, {PlayerCaps: map[int]int{2: -1}}} {
+		if _, err := NewGame(Config{SmallBlind: 1, BigBlind: 2, Rake: r}, []int{10, 10}, 0, stackedDeck(t, "", "2c 3c 4c 5c 6c")); err == nil {
+			t.Errorf("NewGame with rake %+v: want error but got nil", r)
+		}
+	}
+}
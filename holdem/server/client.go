@@ -0,0 +1,63 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/yuzuy/poker/holdem"
+)
+
+// Client is a connection to a server, for tests and bots.
+type Client struct {
+	ws *websocket.Conn
+}
+
+// Dial connects to the WebSocket URL of a server.
+func Dial(url string) (*Client, error) {
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{ws: ws}, nil
+}
+
+func (c *Client) Send(r Request) error {
+	return c.ws.WriteJSON(r)
+}
+
+func (c *Client) Join(seat int, name string) error {
+	return c.Send(Request{Type: Join, Seat: seat, Name: name})
+}
+
+func (c *Client) Rejoin(token string) error {
+	return c.Send(Request{Type: Rejoin, Token: token})
+}
+
+func (c *Client) Watch() error {
+	return c.Send(Request{Type: Watch})
+}
+
+func (c *Client) Leave() error {
+	return c.Send(Request{Type: Leave})
+}
+
+func (c *Client) Act(a holdem.Action) error {
+	return c.Send(Request{Type: Act, Action: &a})
+}
+
+// Next returns the next message, waiting for it up to timeout. The client
+// cannot be used after a timeout.
+func (c *Client) Next(timeout time.Duration) (*Message, error) {
+	if err := c.ws.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	var m Message
+	if err := c.ws.ReadJSON(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) Close() error {
+	return c.ws.Close()
+}
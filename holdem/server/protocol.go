@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/yuzuy/poker/holdem"
+)
+
+// Types of requests.
+const (
+	// Join takes Seat under Name. The reply carries a token to rejoin with.
+	Join = "join"
+	// Rejoin takes back the seat of Token after a lost connection.
+	Rejoin = "rejoin"
+	// Watch follows the table as a spectator.
+	Watch = "watch"
+	// Leave gives up the seat once the hand is over, folding meanwhile.
+	Leave = "leave"
+	// Act takes Action when it is the turn of the seat.
+	Act = "act"
+)
+
+// Request is a message from a client.
+type Request struct {
+	Type   string         `json:"type"`
+	Seat   int            `json:"seat,omitempty"`
+	Name   string         `json:"name,omitempty"`
+	Token  string         `json:"token,omitempty"`
+	Action *holdem.Action `json:"action,omitempty"`
+}
+
+// Types of messages.
+const (
+	// Seated tells a client its Seat and Token, or Spectator.
+	Seated = "seated"
+	// Players holds who sits where, sent whenever it changes.
+	Players = "players"
+	// State holds the hand as the client may see it, sent after every
+	// event.
+	State = "state"
+	// EventMessage holds an event of the hand named Event, redacted for the
+	// client.
+	EventMessage = "event"
+	Error        = "error"
+)
+
+// Message is a message to a client.
+type Message struct {
+	Type    string          `json:"type"`
+	Seat    int             `json:"seat"`
+	Token   string          `json:"token,omitempty"`
+	Players []Player        `json:"players,omitempty"`
+	Table   *holdem.Table   `json:"table,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// Player is a player sitting at the table.
+type Player struct {
+	Seat      int    `json:"seat"`
+	Name      string `json:"name"`
+	Stack     int    `json:"stack"`
+	Connected bool   `json:"connected"`
+}
+
+func eventName(e holdem.Event) string {
+	switch e.(type) {
+	case holdem.HandStarted:
+		return "hand_started"
+	case holdem.BlindsPosted:
+		return "blinds_posted"
+	case holdem.CardsDealt:
+		return "cards_dealt"
+	case holdem.ActionTaken:
+		return "action_taken"
+	case holdem.StreetDealt:
+		return "street_dealt"
+	case holdem.BetReturned:
+		return "bet_returned"
+	case holdem.Showdown:
+		return "showdown"
+	case holdem.PotAwarded:
+		return "pot_awarded"
+	case holdem.HandEnded:
+		return "hand_ended"
+	default:
+		return "unknown"
+	}
+}
+
+func eventMessage(e holdem.Event, seat int) (Message, error) {
+	data, err := json.Marshal(newWireEvent(e))
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Type: EventMessage, Seat: seat, Event: eventName(e), Data: data}, nil
+}
+
+// DecodeEvent returns the event an event message holds.
+func (m *Message) DecodeEvent() (holdem.Event, error) {
+	decode, ok := decoders[m.Event]
+	if m.Type != EventMessage || !ok {
+		return nil, fmt.Errorf("server: no event in %s message %q", m.Type, m.Event)
+	}
+	return decode(m.Data)
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+func mustParseCards(t *testing.T, s string) []poker.Card {
+	t.Helper()
+	cards, err := poker.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+// upperKeys returns the keys of the JSON objects in v starting with an
+// upper case letter.
+func upperKeys(v interface{}) []string {
+	var keys []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, x := range v {
+			if unicode.IsUpper([]rune(k)[0]) {
+				keys = append(keys, k)
+			}
+			keys = append(keys, upperKeys(x)...)
+		}
+	case []interface{}:
+		for _, x := range v {
+			keys = append(keys, upperKeys(x)...)
+		}
+	}
+	return keys
+}
+
+func TestMessage_JSON(t *testing.T) {
+	t.Parallel()
+	holes := []poker.PersonalHand{{Cards: mustParseCards(t, "As Kd")}, {}}
+	board := poker.Board{Cards: mustParseCards(t, "2c 7d 9h")}
+	pot := holdem.Pot{Amount: 20, Rake: 1, Eligible: []int{0, 1}, Winners: []int{0}}
+	result := &holdem.Result{
+		Stacks: []int{110, 90},
+		Net:    []int{10, -10},
+		Pots:   []holdem.Pot{pot},
+		Board:  board,
+		Rake:   1,
+		Ledger: []holdem.Entry{{Kind: holdem.Raked, Seat: -1, Pot: 0, Amount: 1}},
+	}
+	cfg := holdem.Config{SmallBlind: 1, BigBlind: 2, Rake: holdem.Rake{Percent: 0.05, PlayerCaps: map[int]int{2: 1}}}
+	tests := []struct {
+		name  string
+		event holdem.Event
+		table *holdem.Table
+		want  []string
+	}{
+		{
+			name:  "hand started",
+			event: holdem.HandStarted{Config: cfg, Button: 1, Stacks: []int{100, 100}, Deck: board.Cards},
+			want:  []string{`"config":{"small_blind":1,"big_blind":2,"ante":0,"rake":{"percent":0.05,"cap":0,"player_caps":{"2":1}`, `"deck":["2c","7d","9h"]`},
+		},
+		{
+			name:  "blinds posted",
+			event: holdem.BlindsPosted{Antes: []int{0, 0}, SmallBlindSeat: 1, SmallBlind: 1, BigBlind: 2},
+			want:  []string{`"small_blind_seat":1`, `"big_blind_seat":0`},
+		},
+		{
+			name:  "cards dealt",
+			event: holdem.CardsDealt{Seat: 0, Hole: holes[0]},
+			want:  []string{`"hole":{"cards":["As","Kd"]}`},
+		},
+		{
+			name: "action taken",
+			event: holdem.ActionTaken{
+				PlayerAction: holdem.PlayerAction{Seat: 1, Street: holdem.Preflop, Action: holdem.Action{Kind: holdem.Raise, Amount: 6}},
+				Amount:       5,
+			},
+			want: []string{`"seat":1`, `"street":"preflop"`, `"action":{"kind":"raise","amount":6}`, `"amount":5}`},
+		},
+		{
+			name:  "street dealt",
+			event: holdem.StreetDealt{Street: holdem.Flop, Cards: board.Cards},
+			want:  []string{`"street":"flop"`, `"cards":["2c","7d","9h"]`},
+		},
+		{
+			name:  "bet returned",
+			event: holdem.BetReturned{Seat: 1, Amount: 4},
+			want:  []string{`"seat":1`, `"amount":4`},
+		},
+		{
+			name:  "showdown",
+			event: holdem.Showdown{Holes: holes},
+			want:  []string{`"holes":[{"cards":["As","Kd"]},{"cards":null}]`},
+		},
+		{
+			name:  "pot awarded",
+			event: holdem.PotAwarded{Pot: pot, Won: []int{19, 0}},
+			want:  []string{`"pot":{"amount":20,"rake":1,"jackpot":0,"eligible":[0,1],"winners":[0]}`},
+		},
+		{
+			name:  "hand ended",
+			event: holdem.HandEnded{Result: result},
+			want:  []string{`"board":{"cards":["2c","7d","9h"]}`, `"stacks":[110,90]`, `"ledger":[{"kind":"raked","seat":-1,"pot":0,"amount":1}]`},
+		},
+		{
+			name: "table",
+			table: &holdem.Table{
+				Config: cfg,
+				Holes:  holes,
+				Board:  board,
+				Street: holdem.Flop,
+				Stacks: []int{98, 98},
+				Bets:   []int{0, 0},
+				Folded: []bool{false, false},
+				Pot:    4,
+				ToAct:  -1,
+				History: []holdem.PlayerAction{
+					{Seat: 1, Street: holdem.Preflop, Action: holdem.Action{Kind: holdem.Call}},
+				},
+				Result: result,
+			},
+			want: []string{
+				`"holes":[{"cards":["As","Kd"]}`,
+				`"board":{"cards":["2c","7d","9h"]}`,
+				`"to_act":-1`,
+				`"history":[{"seat":1,"street":"preflop","action":{"kind":"call","amount":0}}]`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m := Message{Type: State, Table: tt.table}
+			if tt.event != nil {
+				var err error
+				if m, err = eventMessage(tt.event, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			b, err := json.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(b), want) {
+					t.Errorf("want is %s in it, but got %s", want, b)
+				}
+			}
+			var v interface{}
+			if err := json.Unmarshal(b, &v); err != nil {
+				t.Fatal(err)
+			}
+			if keys := upperKeys(v); len(keys) > 0 {
+				t.Errorf("want is no upper case key, but got %v in %s", keys, b)
+			}
+
+			var got Message
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if tt.event == nil {
+				if diff := cmp.Diff(got.Table, tt.table, cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("want and got are different(-got +want): %s", diff)
+				}
+				return
+			}
+			e, err := got.DecodeEvent()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(e, tt.event, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMessage_JSON_PlayedHand(t *testing.T) {
+	t.Parallel()
+	log := &holdem.Log{}
+	cfg := holdem.Config{SmallBlind: 1, BigBlind: 2, Rake: holdem.Rake{Percent: 0.05}}
+	g, err := holdem.NewGame(cfg, []int{100, 100, 100}, 0, poker.NewSeededDeck(1), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := holdem.AgentFunc((*holdem.View).CheckOrCall)
+	g.Play([]holdem.Agent{call, call, call})
+
+	hands := cmp.AllowUnexported(poker.Hand{})
+	for n, e := range log.Events() {
+		m, err := eventMessage(e, holdem.Spectator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Message
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := got.DecodeEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(decoded, e, hands, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("event %d: want and got are different(-got +want): %s", n, diff)
+		}
+
+		s, err := log.At(n + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		table := s.Table(0)
+		if b, err = json.Marshal(Message{Type: State, Table: table}); err != nil {
+			t.Fatal(err)
+		}
+		got = Message{}
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got.Table, table, hands, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("table %d: want and got are different(-got +want): %s", n, diff)
+		}
+	}
+}
+
+func TestRequest_JSON(t *testing.T) {
+	t.Parallel()
+	r := Request{Type: Act, Action: &holdem.Action{Kind: holdem.Raise, Amount: 30}}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"act","action":{"kind":"raise","amount":30}}`
+	if string(b) != want {
+		t.Fatalf("want is %s, but got %s", want, b)
+	}
+	var got Request
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, r); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+
+	if _, err := json.Marshal(Request{Type: Act, Action: &holdem.Action{}}); err == nil {
+		t.Error("action without a kind was marshaled")
+	}
+	if err := json.Unmarshal([]byte(`{"type":"act","action":{"kind":"shove"}}`), &Request{}); err == nil {
+		t.Error("unknown action kind was unmarshaled")
+	}
+	if err := json.Unmarshal([]byte(`{"type":"state","table":{"street":"fifth"}}`), &Message{}); err == nil {
+		t.Error("unknown street was unmarshaled")
+	}
+}
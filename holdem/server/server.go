@@ -0,0 +1,209 @@
+// Package server hosts Hold'em tables played over WebSocket with a JSON
+// protocol.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+type Config struct {
+	Game holdem.Config
+	// Seats is the number of seats of each table. It defaults to 6.
+	Seats int
+	// Stack is what a player sits down with.
+	Stack int
+	// ActionTimeout is how long a player has to act before it checks or
+	// folds. It defaults to 30 seconds.
+	ActionTimeout time.Duration
+	// ReconnectTimeout is how long a disconnected player keeps its seat.
+	// It defaults to a minute.
+	ReconnectTimeout time.Duration
+	// HandDelay is the pause between hands.
+	HandDelay time.Duration
+	Seed      int64
+}
+
+// Server hosts tables named by the table query parameter of the
+// WebSocket URL, "main" by default.
+type Server struct {
+	cfg      Config
+	upgrader websocket.Upgrader
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	rnd    *mathrand.Rand
+	tables map[string]*table
+	conns  map[*conn]bool
+	closed bool
+}
+
+func New(cfg Config) (*Server, error) {
+	if cfg.Seats == 0 {
+		cfg.Seats = 6
+	}
+	if cfg.ActionTimeout == 0 {
+		cfg.ActionTimeout = 30 * time.Second
+	}
+	if cfg.ReconnectTimeout == 0 {
+		cfg.ReconnectTimeout = time.Minute
+	}
+	if cfg.Seats < 2 || cfg.Stack <= 0 {
+		return nil, errors.New("server: invalid seats or stack")
+	}
+	if _, err := holdem.NewGame(cfg.Game, []int{cfg.Stack, cfg.Stack}, 0, poker.FullCardSet.Deck()); err != nil {
+		return nil, err
+	}
+	return &Server{
+		cfg:    cfg,
+		done:   make(chan struct{}),
+		rnd:    mathrand.New(mathrand.NewSource(cfg.Seed)),
+		tables: make(map[string]*table),
+		conns:  make(map[*conn]bool),
+	}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("table")
+	if name == "" {
+		name = "main"
+	}
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ws.Close()
+		return
+	}
+	t, ok := s.tables[name]
+	if !ok {
+		t = newTable(s.cfg, s.rnd.Int63(), s.done)
+		s.tables[name] = t
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			t.run()
+		}()
+	}
+	c := newConn(ws, t)
+	s.conns[c] = true
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go c.write()
+	c.read()
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+// Close disconnects every client and stops the tables.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	for c := range s.conns {
+		c.ws.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+// conn is a client connection. Messages go out through send so that only
+// write writes to ws.
+type conn struct {
+	ws    *websocket.Conn
+	table *table
+	send  chan Message
+	once  sync.Once
+	// seat is the seat of the client, or holdem.Spectator, and closed is
+	// set once it is detached. They are guarded by the mutex of the table.
+	seat   int
+	closed bool
+}
+
+func newConn(ws *websocket.Conn, t *table) *conn {
+	return &conn{ws: ws, table: t, send: make(chan Message, 256), seat: holdem.Spectator}
+}
+
+func (c *conn) read() {
+	defer c.close()
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var r Request
+		if err := json.Unmarshal(data, &r); err != nil {
+			c.table.mu.Lock()
+			c.fail(err)
+			c.table.mu.Unlock()
+			continue
+		}
+		c.table.handle(c, r)
+	}
+}
+
+func (c *conn) write() {
+	for m := range c.send {
+		if err := c.ws.WriteJSON(m); err != nil {
+			c.ws.Close()
+		}
+	}
+	c.ws.Close()
+}
+
+// deliver queues m, dropping clients too slow to keep up. The caller
+// holds the mutex of the table.
+func (c *conn) deliver(m Message) {
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- m:
+	default:
+		c.ws.Close()
+	}
+}
+
+// fail tells the client of err. The caller holds the mutex of the table.
+func (c *conn) fail(err error) {
+	c.deliver(Message{Type: Error, Seat: c.seat, Error: err.Error()})
+}
+
+// close detaches the client from its table once its connection is gone.
+func (c *conn) close() {
+	c.once.Do(func() {
+		c.table.detach(c)
+		close(c.send)
+	})
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("server: cannot make a token: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker/holdem"
+)
+
+const timeout = 5 * time.Second
+
+func newTestServer(t *testing.T, cfg Config) string {
+	t.Helper()
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		srv.Close()
+		ts.Close()
+	})
+	return "ws" + strings.TrimPrefix(ts.URL, "http") + "/?table=test"
+}
+
+func dial(t *testing.T, url string) *Client {
+	t.Helper()
+	c, err := Dial(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// expect returns the first message for which ok returns true.
+func expect(t *testing.T, c *Client, ok func(m *Message) bool) *Message {
+	t.Helper()
+	for {
+		m, err := c.Next(timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok(m) {
+			return m
+		}
+	}
+}
+
+func isType(typ string) func(m *Message) bool {
+	return func(m *Message) bool { return m.Type == typ }
+}
+
+func isEvent(name string) func(m *Message) bool {
+	return func(m *Message) bool { return m.Type == EventMessage && m.Event == name }
+}
+
+// play checks or calls for seat until the hand is over and returns the
+// events the client got.
+func play(c *Client, seat int) ([]holdem.Event, error) {
+	var events []holdem.Event
+	acted := -1
+	for {
+		m, err := c.Next(timeout)
+		if err != nil {
+			return nil, err
+		}
+		switch m.Type {
+		case EventMessage:
+			e, err := m.DecodeEvent()
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, e)
+			if _, ok := e.(holdem.HandEnded); ok {
+				return events, nil
+			}
+		case State:
+			tb := m.Table
+			if seat < 0 || tb.ToAct != seat || len(tb.History) == acted {
+				continue
+			}
+			acted = len(tb.History)
+			a := holdem.Action{Kind: holdem.Check}
+			for _, b := range tb.Bets {
+				if b > tb.Bets[seat] {
+					a.Kind = holdem.Call
+				}
+			}
+			if err := c.Act(a); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+	url := newTestServer(t, Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Seats: 3, Stack: 100, Seed: 1})
+	watcher := dial(t, url)
+	if err := watcher.Watch(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, watcher, isType(Seated))
+
+	clients := []*Client{dial(t, url), dial(t, url), watcher}
+	for s, c := range clients[:2] {
+		if err := c.Join(s, ""); err != nil {
+			t.Fatal(err)
+		}
+		if m := expect(t, c, isType(Seated)); m.Seat != s || m.Token == "" {
+			t.Fatalf("want is seat %d with a token, but got seat %d with token %q", s, m.Seat, m.Token)
+		}
+	}
+
+	type result struct {
+		events []holdem.Event
+		err    error
+	}
+	results := make([]chan result, len(clients))
+	for i, c := range clients {
+		results[i] = make(chan result, 1)
+		seat := i
+		if c == watcher {
+			seat = holdem.Spectator
+		}
+		go func(c *Client, ch chan result) {
+			events, err := play(c, seat)
+			ch <- result{events, err}
+		}(c, results[i])
+	}
+
+	for i := range clients {
+		r := <-results[i]
+		if r.err != nil {
+			t.Fatalf("client %d: %v", i, r.err)
+		}
+		seen := make([]int, 2)
+		var showdown, end bool
+		for _, e := range r.events {
+			switch e := e.(type) {
+			case holdem.HandStarted:
+				if e.Deck != nil {
+					t.Errorf("client %d got the deck", i)
+				}
+			case holdem.CardsDealt:
+				seen[e.Seat] = len(e.Hole.Cards)
+			case holdem.Showdown:
+				showdown = len(e.Holes[0].Cards) == 2 && len(e.Holes[1].Cards) == 2
+			case holdem.HandEnded:
+				end = e.Result.Stacks[0]+e.Result.Stacks[1] == 200
+			}
+		}
+		want := []int{0, 0}
+		if i < 2 {
+			want[i] = 2
+		}
+		if diff := cmp.Diff(seen, want); diff != "" {
+			t.Errorf("client %d saw hole cards(-got +want): %s", i, diff)
+		}
+		if !showdown || !end {
+			t.Errorf("client %d: want is a showdown and an end, but got showdown %v and end %v", i, showdown, end)
+		}
+	}
+}
+
+func TestServer_ActionTimeout(t *testing.T) {
+	t.Parallel()
+	url := newTestServer(t, Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Seats: 2, Stack: 100, ActionTimeout: 20 * time.Millisecond})
+	a, b := dial(t, url), dial(t, url)
+	if err := a.Join(0, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Join(1, "b"); err != nil {
+		t.Fatal(err)
+	}
+	// the button posts the small blind and folds when its time is up
+	m := expect(t, a, isEvent("hand_ended"))
+	e, err := m.DecodeEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(e.(holdem.HandEnded).Result.Net, []int{-1, 1}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestServer_Rejoin(t *testing.T) {
+	t.Parallel()
+	url := newTestServer(t, Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Seats: 2, Stack: 100})
+	a, b := dial(t, url), dial(t, url)
+	if err := a.Join(0, "a"); err != nil {
+		t.Fatal(err)
+	}
+	token := expect(t, a, isType(Seated)).Token
+	if err := b.Join(1, "b"); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, a, isEvent("cards_dealt"))
+	a.Close()
+	expect(t, b, func(m *Message) bool {
+		return m.Type == Players && len(m.Players) == 2 && !m.Players[0].Connected
+	})
+
+	again := dial(t, url)
+	if err := again.Rejoin(token); err != nil {
+		t.Fatal(err)
+	}
+	if m := expect(t, again, isType(Seated)); m.Seat != 0 {
+		t.Errorf("want is seat 0 rejoined, but got %d", m.Seat)
+	}
+	m := expect(t, again, isType(State))
+	if len(m.Table.Holes[0].Cards) != 2 || len(m.Table.Holes[1].Cards) != 0 {
+		t.Errorf("want is only its own hole rejoined, but got %v", m.Table.Holes)
+	}
+}
+
+func TestServer_Error(t *testing.T) {
+	t.Parallel()
+	url := newTestServer(t, Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2}, Seats: 2, Stack: 100})
+	a, b := dial(t, url), dial(t, url)
+	if err := a.Join(0, "a"); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, a, isType(Seated))
+	tests := []struct {
+		name string
+		r    Request
+	}{
+		{name: "seat taken", r: Request{Type: Join, Seat: 0}},
+		{name: "no seat", r: Request{Type: Join, Seat: 2}},
+		{name: "unknown token", r: Request{Type: Rejoin, Token: "x"}},
+		{name: "act without a seat", r: Request{Type: Act, Action: &holdem.Action{Kind: holdem.Check}}},
+		{name: "leave without a seat", r: Request{Type: Leave}},
+		{name: "unknown request", r: Request{Type: "shuffle"}},
+	}
+	for _, tt := range tests {
+		if err := b.Send(tt.r); err != nil {
+			t.Fatal(err)
+		}
+		if m := expect(t, b, func(m *Message) bool { return m.Type != Players }); m.Type != Error {
+			t.Errorf("%s: want is an error message, but got %s", tt.name, m.Type)
+		}
+	}
+	if _, err := New(Config{Stack: 100}); err == nil {
+		t.Error("New without blinds: want error but got nil")
+	}
+}
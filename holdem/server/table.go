@@ -0,0 +1,380 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+type seat struct {
+	name  string
+	token string
+	stack int
+	// conn is nil while the player is disconnected, since gone.
+	conn    *conn
+	gone    time.Time
+	leaving bool
+}
+
+// action is a request to act from the client c in seat, or to check or
+// fold for it when leave is set.
+type action struct {
+	c      *conn
+	seat   int
+	action holdem.Action
+	leave  bool
+}
+
+// table plays hands in run. Everything else goes through the mutex, which
+// is also held while the game runs so that events reach the clients in
+// order.
+type table struct {
+	cfg     Config
+	rnd     *mathrand.Rand
+	done    <-chan struct{}
+	actions chan action
+	wake    chan struct{}
+
+	mu       sync.Mutex
+	seats    []*seat
+	watchers map[*conn]bool
+	state    *holdem.State
+	button   int
+}
+
+func newTable(cfg Config, seed int64, done <-chan struct{}) *table {
+	return &table{
+		cfg:      cfg,
+		rnd:      mathrand.New(mathrand.NewSource(seed)),
+		done:     done,
+		actions:  make(chan action, 16),
+		wake:     make(chan struct{}, 1),
+		seats:    make([]*seat, cfg.Seats),
+		watchers: make(map[*conn]bool),
+		button:   -1,
+	}
+}
+
+func (t *table) handle(c *conn, r Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var err error
+	switch r.Type {
+	case Join:
+		err = t.join(c, r.Seat, r.Name)
+	case Rejoin:
+		err = t.rejoin(c, r.Token)
+	case Watch:
+		if c.seat != holdem.Spectator {
+			err = errors.New("server: already seated")
+			break
+		}
+		t.watchers[c] = true
+		c.deliver(Message{Type: Seated, Seat: holdem.Spectator})
+		c.deliver(t.players())
+		t.sendState(c)
+	case Leave:
+		if c.seat == holdem.Spectator {
+			err = errors.New("server: not seated")
+			break
+		}
+		t.seats[c.seat].leaving = true
+		t.queue(action{c: c, seat: c.seat, leave: true})
+		t.signal()
+	case Act:
+		switch {
+		case c.seat == holdem.Spectator:
+			err = errors.New("server: not seated")
+		case r.Action == nil:
+			err = errors.New("server: no action")
+		case !t.queue(action{c: c, seat: c.seat, action: *r.Action}):
+			err = errors.New("server: too many actions")
+		}
+	default:
+		err = fmt.Errorf("server: unknown request %q", r.Type)
+	}
+	if err != nil {
+		c.fail(err)
+	}
+}
+
+func (t *table) join(c *conn, s int, name string) error {
+	if c.seat != holdem.Spectator {
+		return errors.New("server: already seated")
+	}
+	if s < 0 || s >= len(t.seats) {
+		return fmt.Errorf("server: no seat %d", s)
+	}
+	if t.seats[s] != nil {
+		return fmt.Errorf("server: seat %d is taken", s)
+	}
+	if name == "" {
+		name = fmt.Sprintf("seat %d", s)
+	}
+	st := &seat{name: name, token: newToken(), stack: t.cfg.Stack, conn: c}
+	t.seats[s] = st
+	c.seat = s
+	delete(t.watchers, c)
+	c.deliver(Message{Type: Seated, Seat: s, Token: st.token})
+	t.broadcast(t.players())
+	t.sendState(c)
+	t.signal()
+	return nil
+}
+
+func (t *table) rejoin(c *conn, token string) error {
+	if c.seat != holdem.Spectator {
+		return errors.New("server: already seated")
+	}
+	for s, st := range t.seats {
+		if st == nil || st.token != token || token == "" {
+			continue
+		}
+		if old := st.conn; old != nil {
+			old.seat = holdem.Spectator
+			old.ws.Close()
+		}
+		st.conn, st.gone = c, time.Time{}
+		c.seat = s
+		delete(t.watchers, c)
+		c.deliver(Message{Type: Seated, Seat: s, Token: st.token})
+		t.broadcast(t.players())
+		t.sendState(c)
+		return nil
+	}
+	return errors.New("server: unknown token")
+}
+
+// detach forgets c, keeping its seat for a while.
+func (t *table) detach(c *conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.watchers, c)
+	c.closed = true
+	if c.seat == holdem.Spectator {
+		return
+	}
+	st := t.seats[c.seat]
+	st.conn, st.gone = nil, time.Now()
+	c.seat = holdem.Spectator
+	t.broadcast(t.players())
+}
+
+func (t *table) queue(a action) bool {
+	select {
+	case t.actions <- a:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *table) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (t *table) players() Message {
+	m := Message{Type: Players, Seat: holdem.Spectator}
+	for s, st := range t.seats {
+		if st != nil {
+			m.Players = append(m.Players, Player{Seat: s, Name: st.name, Stack: st.stack, Connected: st.conn != nil})
+		}
+	}
+	return m
+}
+
+func (t *table) conns() []*conn {
+	var conns []*conn
+	for _, st := range t.seats {
+		if st != nil && st.conn != nil {
+			conns = append(conns, st.conn)
+		}
+	}
+	for c := range t.watchers {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (t *table) broadcast(m Message) {
+	for _, c := range t.conns() {
+		m.Seat = c.seat
+		c.deliver(m)
+	}
+}
+
+func (t *table) sendState(c *conn) {
+	if t.state != nil {
+		c.deliver(Message{Type: State, Seat: c.seat, Table: t.state.Table(c.seat)})
+	}
+}
+
+// observe passes e on to every client as it may see it.
+func (t *table) observe(e holdem.Event) {
+	prev := t.state
+	if _, ok := e.(holdem.HandStarted); ok {
+		prev = nil
+	}
+	if s, err := holdem.Reduce(prev, e); err == nil {
+		t.state = s
+	}
+	for _, c := range t.conns() {
+		if m, err := eventMessage(holdem.Redact(e, c.seat), c.seat); err == nil {
+			c.deliver(m)
+		}
+		t.sendState(c)
+	}
+}
+
+func (t *table) run() {
+	for {
+		t.mu.Lock()
+		t.standUp()
+		players := 0
+		for _, st := range t.seats {
+			if st != nil {
+				players++
+			}
+		}
+		t.mu.Unlock()
+
+		if players < 2 {
+			select {
+			case <-t.wake:
+				continue
+			case <-t.done:
+				return
+			}
+		}
+		if !t.playHand() {
+			return
+		}
+		select {
+		case <-time.After(t.cfg.HandDelay):
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// standUp frees the seats of players who left, went broke or did not come
+// back in time.
+func (t *table) standUp() {
+	changed := false
+	for s, st := range t.seats {
+		if st == nil {
+			continue
+		}
+		if !st.leaving && st.stack > 0 && (st.conn != nil || time.Since(st.gone) < t.cfg.ReconnectTimeout) {
+			continue
+		}
+		t.seats[s] = nil
+		changed = true
+		if c := st.conn; c != nil {
+			c.seat = holdem.Spectator
+			t.watchers[c] = true
+			c.deliver(Message{Type: Seated, Seat: holdem.Spectator})
+		}
+	}
+	if changed {
+		t.broadcast(t.players())
+	}
+}
+
+// playHand deals a hand and plays it out, returning false when the server
+// closes.
+func (t *table) playHand() bool {
+	for len(t.actions) > 0 {
+		a := <-t.actions
+		if !a.leave {
+			t.mu.Lock()
+			a.c.fail(errors.New("server: no action is due"))
+			t.mu.Unlock()
+		}
+	}
+
+	t.mu.Lock()
+	stacks := make([]int, len(t.seats))
+	for s, st := range t.seats {
+		if st != nil {
+			stacks[s] = st.stack
+		}
+	}
+	for t.button = (t.button + 1) % len(t.seats); stacks[t.button] == 0; {
+		t.button = (t.button + 1) % len(t.seats)
+	}
+	g, err := holdem.NewGame(t.cfg.Game, stacks, t.button, poker.NewSeededDeck(t.rnd.Int63()), holdem.ObserverFunc(t.observe))
+	t.mu.Unlock()
+	if err != nil {
+		return false
+	}
+
+	for {
+		t.mu.Lock()
+		s := g.ToAct()
+		if s < 0 {
+			for i, stack := range g.Result().Stacks {
+				if stacks[i] > 0 {
+					t.seats[i].stack = stack
+				}
+			}
+			t.broadcast(t.players())
+			t.mu.Unlock()
+			return true
+		}
+		if t.seats[s].leaving {
+			_ = g.Act(g.View(s).CheckOrFold())
+			t.mu.Unlock()
+			continue
+		}
+		t.mu.Unlock()
+		if !t.wait(g, s) {
+			return false
+		}
+	}
+}
+
+// wait applies the action of the player in s, or checks or folds for it
+// once its time is up.
+func (t *table) wait(g *holdem.Game, s int) bool {
+	timer := time.NewTimer(t.cfg.ActionTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case a := <-t.actions:
+			t.mu.Lock()
+			var err error
+			switch {
+			case a.seat != s && a.leave:
+			case a.seat != s:
+				err = errors.New("server: not your turn")
+			case a.leave:
+				_ = g.Act(g.View(s).CheckOrFold())
+			default:
+				err = g.Act(a.action)
+			}
+			if err != nil {
+				a.c.fail(err)
+			}
+			t.mu.Unlock()
+			if err == nil && a.seat == s {
+				return true
+			}
+		case <-timer.C:
+			t.mu.Lock()
+			_ = g.Act(g.View(s).CheckOrFold())
+			t.mu.Unlock()
+			return true
+		case <-t.done:
+			return false
+		}
+	}
+}
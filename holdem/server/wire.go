@@ -0,0 +1,479 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+// The protocol sends the types of package holdem in the shapes below, so
+// that they can change without changing what goes over the wire.
+
+var actionKindNames = map[holdem.ActionKind]string{
+	holdem.Fold:  "fold",
+	holdem.Check: "check",
+	holdem.Call:  "call",
+	holdem.Bet:   "bet",
+	holdem.Raise: "raise",
+}
+
+type wireActionKind holdem.ActionKind
+
+func (k wireActionKind) MarshalText() ([]byte, error) {
+	if name, ok := actionKindNames[holdem.ActionKind(k)]; ok {
+		return []byte(name), nil
+	}
+	return nil, fmt.Errorf("server: invalid action kind %d", int(k))
+}
+
+func (k *wireActionKind) UnmarshalText(text []byte) error {
+	for kind, name := range actionKindNames {
+		if name == string(text) {
+			*k = wireActionKind(kind)
+			return nil
+		}
+	}
+	return fmt.Errorf("server: invalid action kind %q", text)
+}
+
+var streetNames = map[holdem.Street]string{
+	holdem.Preflop: "preflop",
+	holdem.Flop:    "flop",
+	holdem.Turn:    "turn",
+	holdem.River:   "river",
+}
+
+type wireStreet holdem.Street
+
+func (s wireStreet) MarshalText() ([]byte, error) {
+	if name, ok := streetNames[holdem.Street(s)]; ok {
+		return []byte(name), nil
+	}
+	return nil, fmt.Errorf("server: invalid street %d", int(s))
+}
+
+func (s *wireStreet) UnmarshalText(text []byte) error {
+	for street, name := range streetNames {
+		if name == string(text) {
+			*s = wireStreet(street)
+			return nil
+		}
+	}
+	return fmt.Errorf("server: invalid street %q", text)
+}
+
+var entryKindNames = map[holdem.EntryKind]string{
+	holdem.Contributed: "contributed",
+	holdem.Returned:    "returned",
+	holdem.Raked:       "raked",
+	holdem.Dropped:     "dropped",
+	holdem.Won:         "won",
+}
+
+type wireEntryKind holdem.EntryKind
+
+func (k wireEntryKind) MarshalText() ([]byte, error) {
+	if name, ok := entryKindNames[holdem.EntryKind(k)]; ok {
+		return []byte(name), nil
+	}
+	return nil, fmt.Errorf("server: invalid entry kind %d", int(k))
+}
+
+func (k *wireEntryKind) UnmarshalText(text []byte) error {
+	for kind, name := range entryKindNames {
+		if name == string(text) {
+			*k = wireEntryKind(kind)
+			return nil
+		}
+	}
+	return fmt.Errorf("server: invalid entry kind %q", text)
+}
+
+// wireCards is a poker.PersonalHand or poker.Board.
+type wireCards struct {
+	Cards []poker.Card `json:"cards"`
+}
+
+func newWireHoles(holes []poker.PersonalHand) []wireCards {
+	if holes == nil {
+		return nil
+	}
+	w := make([]wireCards, len(holes))
+	for i, h := range holes {
+		w[i].Cards = h.Cards
+	}
+	return w
+}
+
+func personalHands(w []wireCards) []poker.PersonalHand {
+	if w == nil {
+		return nil
+	}
+	holes := make([]poker.PersonalHand, len(w))
+	for i, h := range w {
+		holes[i].Cards = h.Cards
+	}
+	return holes
+}
+
+type wireAction struct {
+	Kind   wireActionKind `json:"kind"`
+	Amount int            `json:"amount"`
+}
+
+type wirePlayerAction struct {
+	Seat   int        `json:"seat"`
+	Street wireStreet `json:"street"`
+	Action wireAction `json:"action"`
+}
+
+func newWirePlayerAction(pa holdem.PlayerAction) wirePlayerAction {
+	return wirePlayerAction{
+		Seat:   pa.Seat,
+		Street: wireStreet(pa.Street),
+		Action: wireAction{Kind: wireActionKind(pa.Action.Kind), Amount: pa.Action.Amount},
+	}
+}
+
+func (w wirePlayerAction) playerAction() holdem.PlayerAction {
+	return holdem.PlayerAction{
+		Seat:   w.Seat,
+		Street: holdem.Street(w.Street),
+		Action: holdem.Action{Kind: holdem.ActionKind(w.Action.Kind), Amount: w.Action.Amount},
+	}
+}
+
+type wireRake struct {
+	Percent       float64     `json:"percent"`
+	Cap           int         `json:"cap"`
+	PlayerCaps    map[int]int `json:"player_caps"`
+	NoFlopNoDrop  bool        `json:"no_flop_no_drop"`
+	JackpotDrop   int         `json:"jackpot_drop"`
+	JackpotMinPot int         `json:"jackpot_min_pot"`
+}
+
+type wireConfig struct {
+	SmallBlind int      `json:"small_blind"`
+	BigBlind   int      `json:"big_blind"`
+	Ante       int      `json:"ante"`
+	Rake       wireRake `json:"rake"`
+}
+
+func newWireConfig(cfg holdem.Config) wireConfig {
+	return wireConfig{SmallBlind: cfg.SmallBlind, BigBlind: cfg.BigBlind, Ante: cfg.Ante, Rake: wireRake(cfg.Rake)}
+}
+
+func (w wireConfig) config() holdem.Config {
+	return holdem.Config{SmallBlind: w.SmallBlind, BigBlind: w.BigBlind, Ante: w.Ante, Rake: holdem.Rake(w.Rake)}
+}
+
+type wirePot struct {
+	Amount   int   `json:"amount"`
+	Rake     int   `json:"rake"`
+	Jackpot  int   `json:"jackpot"`
+	Eligible []int `json:"eligible"`
+	Winners  []int `json:"winners"`
+}
+
+type wireEntry struct {
+	Kind   wireEntryKind `json:"kind"`
+	Seat   int           `json:"seat"`
+	Pot    int           `json:"pot"`
+	Amount int           `json:"amount"`
+}
+
+type wireResult struct {
+	Stacks   []int         `json:"stacks"`
+	Net      []int         `json:"net"`
+	Pots     []wirePot     `json:"pots"`
+	Board    wireCards     `json:"board"`
+	Hands    []*poker.Hand `json:"hands"`
+	Showdown bool          `json:"showdown"`
+	Rake     int           `json:"rake"`
+	Jackpot  int           `json:"jackpot"`
+	Ledger   []wireEntry   `json:"ledger"`
+}
+
+func newWireResult(r *holdem.Result) *wireResult {
+	if r == nil {
+		return nil
+	}
+	w := &wireResult{
+		Stacks:   r.Stacks,
+		Net:      r.Net,
+		Board:    wireCards{r.Board.Cards},
+		Hands:    r.Hands,
+		Showdown: r.Showdown,
+		Rake:     r.Rake,
+		Jackpot:  r.Jackpot,
+	}
+	for _, p := range r.Pots {
+		w.Pots = append(w.Pots, wirePot(p))
+	}
+	for _, e := range r.Ledger {
+		w.Ledger = append(w.Ledger, wireEntry{Kind: wireEntryKind(e.Kind), Seat: e.Seat, Pot: e.Pot, Amount: e.Amount})
+	}
+	return w
+}
+
+func (w *wireResult) result() *holdem.Result {
+	if w == nil {
+		return nil
+	}
+	r := &holdem.Result{
+		Stacks:   w.Stacks,
+		Net:      w.Net,
+		Board:    poker.Board{Cards: w.Board.Cards},
+		Hands:    w.Hands,
+		Showdown: w.Showdown,
+		Rake:     w.Rake,
+		Jackpot:  w.Jackpot,
+	}
+	for _, p := range w.Pots {
+		r.Pots = append(r.Pots, holdem.Pot(p))
+	}
+	for _, e := range w.Ledger {
+		r.Ledger = append(r.Ledger, holdem.Entry{Kind: holdem.EntryKind(e.Kind), Seat: e.Seat, Pot: e.Pot, Amount: e.Amount})
+	}
+	return r
+}
+
+type wireTable struct {
+	Seat    int                `json:"seat"`
+	Config  wireConfig         `json:"config"`
+	Button  int                `json:"button"`
+	Holes   []wireCards        `json:"holes"`
+	Board   wireCards          `json:"board"`
+	Street  wireStreet         `json:"street"`
+	Stacks  []int              `json:"stacks"`
+	Bets    []int              `json:"bets"`
+	Folded  []bool             `json:"folded"`
+	Pot     int                `json:"pot"`
+	ToAct   int                `json:"to_act"`
+	History []wirePlayerAction `json:"history"`
+	Result  *wireResult        `json:"result"`
+}
+
+func newWireTable(t *holdem.Table) *wireTable {
+	if t == nil {
+		return nil
+	}
+	w := &wireTable{
+		Seat:   t.Seat,
+		Config: newWireConfig(t.Config),
+		Button: t.Button,
+		Holes:  newWireHoles(t.Holes),
+		Board:  wireCards{t.Board.Cards},
+		Street: wireStreet(t.Street),
+		Stacks: t.Stacks,
+		Bets:   t.Bets,
+		Folded: t.Folded,
+		Pot:    t.Pot,
+		ToAct:  t.ToAct,
+		Result: newWireResult(t.Result),
+	}
+	for _, pa := range t.History {
+		w.History = append(w.History, newWirePlayerAction(pa))
+	}
+	return w
+}
+
+func (w *wireTable) table() *holdem.Table {
+	if w == nil {
+		return nil
+	}
+	t := &holdem.Table{
+		Seat:   w.Seat,
+		Config: w.Config.config(),
+		Button: w.Button,
+		Holes:  personalHands(w.Holes),
+		Board:  poker.Board{Cards: w.Board.Cards},
+		Street: holdem.Street(w.Street),
+		Stacks: w.Stacks,
+		Bets:   w.Bets,
+		Folded: w.Folded,
+		Pot:    w.Pot,
+		ToAct:  w.ToAct,
+		Result: w.Result.result(),
+	}
+	for _, pa := range w.History {
+		t.History = append(t.History, pa.playerAction())
+	}
+	return t
+}
+
+type wireHandStarted struct {
+	Config wireConfig   `json:"config"`
+	Button int          `json:"button"`
+	Stacks []int        `json:"stacks"`
+	Deck   []poker.Card `json:"deck"`
+}
+
+type wireBlindsPosted struct {
+	Antes          []int `json:"antes"`
+	SmallBlindSeat int   `json:"small_blind_seat"`
+	SmallBlind     int   `json:"small_blind"`
+	BigBlindSeat   int   `json:"big_blind_seat"`
+	BigBlind       int   `json:"big_blind"`
+}
+
+type wireCardsDealt struct {
+	Seat int       `json:"seat"`
+	Hole wireCards `json:"hole"`
+}
+
+type wireActionTaken struct {
+	wirePlayerAction
+	Amount int `json:"amount"`
+}
+
+type wireStreetDealt struct {
+	Street wireStreet   `json:"street"`
+	Cards  []poker.Card `json:"cards"`
+}
+
+type wireBetReturned struct {
+	Seat   int `json:"seat"`
+	Amount int `json:"amount"`
+}
+
+type wireShowdown struct {
+	Holes []wireCards   `json:"holes"`
+	Hands []*poker.Hand `json:"hands"`
+}
+
+type wirePotAwarded struct {
+	Index int     `json:"index"`
+	Pot   wirePot `json:"pot"`
+	Won   []int   `json:"won"`
+}
+
+type wireHandEnded struct {
+	Result *wireResult `json:"result"`
+}
+
+// newWireEvent returns e in the shape it is sent in.
+func newWireEvent(e holdem.Event) interface{} {
+	switch e := e.(type) {
+	case holdem.HandStarted:
+		return wireHandStarted{Config: newWireConfig(e.Config), Button: e.Button, Stacks: e.Stacks, Deck: e.Deck}
+	case holdem.BlindsPosted:
+		return wireBlindsPosted(e)
+	case holdem.CardsDealt:
+		return wireCardsDealt{Seat: e.Seat, Hole: wireCards{e.Hole.Cards}}
+	case holdem.ActionTaken:
+		return wireActionTaken{wirePlayerAction: newWirePlayerAction(e.PlayerAction), Amount: e.Amount}
+	case holdem.StreetDealt:
+		return wireStreetDealt{Street: wireStreet(e.Street), Cards: e.Cards}
+	case holdem.BetReturned:
+		return wireBetReturned(e)
+	case holdem.Showdown:
+		return wireShowdown{Holes: newWireHoles(e.Holes), Hands: e.Hands}
+	case holdem.PotAwarded:
+		return wirePotAwarded{Index: e.Index, Pot: wirePot(e.Pot), Won: e.Won}
+	case holdem.HandEnded:
+		return wireHandEnded{Result: newWireResult(e.Result)}
+	default:
+		return e
+	}
+}
+
+// decoders decode the data of event messages by event name.
+var decoders = map[string]func(data []byte) (holdem.Event, error){
+	"hand_started": func(data []byte) (holdem.Event, error) {
+		var w wireHandStarted
+		err := json.Unmarshal(data, &w)
+		return holdem.HandStarted{Config: w.Config.config(), Button: w.Button, Stacks: w.Stacks, Deck: w.Deck}, err
+	},
+	"blinds_posted": func(data []byte) (holdem.Event, error) {
+		var w wireBlindsPosted
+		err := json.Unmarshal(data, &w)
+		return holdem.BlindsPosted(w), err
+	},
+	"cards_dealt": func(data []byte) (holdem.Event, error) {
+		var w wireCardsDealt
+		err := json.Unmarshal(data, &w)
+		return holdem.CardsDealt{Seat: w.Seat, Hole: poker.PersonalHand{Cards: w.Hole.Cards}}, err
+	},
+	"action_taken": func(data []byte) (holdem.Event, error) {
+		var w wireActionTaken
+		err := json.Unmarshal(data, &w)
+		return holdem.ActionTaken{PlayerAction: w.playerAction(), Amount: w.Amount}, err
+	},
+	"street_dealt": func(data []byte) (holdem.Event, error) {
+		var w wireStreetDealt
+		err := json.Unmarshal(data, &w)
+		return holdem.StreetDealt{Street: holdem.Street(w.Street), Cards: w.Cards}, err
+	},
+	"bet_returned": func(data []byte) (holdem.Event, error) {
+		var w wireBetReturned
+		err := json.Unmarshal(data, &w)
+		return holdem.BetReturned(w), err
+	},
+	"showdown": func(data []byte) (holdem.Event, error) {
+		var w wireShowdown
+		err := json.Unmarshal(data, &w)
+		return holdem.Showdown{Holes: personalHands(w.Holes), Hands: w.Hands}, err
+	},
+	"pot_awarded": func(data []byte) (holdem.Event, error) {
+		var w wirePotAwarded
+		err := json.Unmarshal(data, &w)
+		return holdem.PotAwarded{Index: w.Index, Pot: holdem.Pot(w.Pot), Won: w.Won}, err
+	},
+	"hand_ended": func(data []byte) (holdem.Event, error) {
+		var w wireHandEnded
+		err := json.Unmarshal(data, &w)
+		return holdem.HandEnded{Result: w.Result.result()}, err
+	},
+}
+
+func (r Request) MarshalJSON() ([]byte, error) {
+	type request Request
+	w := struct {
+		request
+		Action *wireAction `json:"action,omitempty"`
+	}{request: request(r)}
+	if r.Action != nil {
+		w.Action = &wireAction{Kind: wireActionKind(r.Action.Kind), Amount: r.Action.Amount}
+	}
+	return json.Marshal(w)
+}
+
+func (r *Request) UnmarshalJSON(data []byte) error {
+	type request Request
+	w := struct {
+		*request
+		Action *wireAction `json:"action,omitempty"`
+	}{request: (*request)(r)}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	r.Action = nil
+	if w.Action != nil {
+		r.Action = &holdem.Action{Kind: holdem.ActionKind(w.Action.Kind), Amount: w.Action.Amount}
+	}
+	return nil
+}
+
+func (m Message) MarshalJSON() ([]byte, error) {
+	type message Message
+	return json.Marshal(struct {
+		message
+		Table *wireTable `json:"table,omitempty"`
+	}{message(m), newWireTable(m.Table)})
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type message Message
+	w := struct {
+		*message
+		Table *wireTable `json:"table,omitempty"`
+	}{message: (*message)(m)}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	m.Table = w.Table.table()
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package sim plays Hold'em hands between agents to compare their
+// results.
+package sim
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+type Config struct {
+	Game holdem.Config
+	// Stack is what every player starts each hand with.
+	Stack int
+	// Hands is the number of deals. The button moves every deal.
+	Hands int
+	Seed  int64
+	// Duplicate replays every deal once for each rotation of the agents
+	// around the table, so that every agent plays every seat with the same
+	// cards.
+	Duplicate bool
+}
+
+// Stats is how an agent did. A sample is a deal, including all its replays
+// in duplicate mode.
+type Stats struct {
+	Hands int
+	Net   int
+	// BBPer100 is the big blinds won per 100 hands, and CI95 the half
+	// width of its 95% confidence interval.
+	BBPer100 float64
+	CI95     float64
+}
+
+// Run plays the hands and returns the stats of each agent. Agent i sits in
+// seat i, or seat i+r in the r-th replay of a duplicate deal.
+func Run(cfg Config, agents []holdem.Agent) ([]Stats, error) {
+	n := len(agents)
+	if n < 2 {
+		return nil, errors.New("sim: need at least 2 agents")
+	}
+	if cfg.Stack <= 0 || cfg.Hands <= 0 {
+		return nil, errors.New("sim: stack and hands must be positive")
+	}
+	rnd := rand.New(rand.NewSource(cfg.Seed))
+	cards := poker.FullCardSet.Cards()
+	stacks := make([]int, n)
+	seated := make([]holdem.Agent, n)
+	rotations := 1
+	if cfg.Duplicate {
+		rotations = n
+	}
+
+	stats := make([]Stats, n)
+	// sum and sumSq of the big blinds won per deal
+	sum := make([]float64, n)
+	sumSq := make([]float64, n)
+	for deal := 0; deal < cfg.Hands; deal++ {
+		deck := poker.NewDeck(cards...)
+		deck.Shuffle(rnd)
+		won := make([]int, n)
+		for r := 0; r < rotations; r++ {
+			for seat := range seated {
+				seated[seat] = agents[(seat-r+n)%n]
+				stacks[seat] = cfg.Stack
+			}
+			g, err := holdem.NewGame(cfg.Game, stacks, deal%n, poker.NewDeck(deck.Cards...))
+			if err != nil {
+				return nil, err
+			}
+			res := g.Play(seated)
+			for seat, net := range res.Net {
+				won[(seat-r+n)%n] += net
+			}
+		}
+		for i, w := range won {
+			stats[i].Hands += rotations
+			stats[i].Net += w
+			bb := float64(w) / float64(cfg.Game.BigBlind)
+			sum[i] += bb
+			sumSq[i] += bb * bb
+		}
+	}
+
+	samples := float64(cfg.Hands)
+	for i := range stats {
+		mean := sum[i] / samples
+		variance := 0.0
+		if cfg.Hands > 1 {
+			variance = (sumSq[i] - samples*mean*mean) / (samples - 1)
+		}
+		// per deal to per hand, then per 100 hands
+		scale := 100 / float64(rotations)
+		stats[i].BBPer100 = mean * scale
+		stats[i].CI95 = 1.96 * math.Sqrt(math.Max(variance, 0)/samples) * scale
+	}
+	return stats, nil
+}
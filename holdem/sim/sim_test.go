@@ -0,0 +1,106 @@
+package sim
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker/holdem"
+	"github.com/yuzuy/poker/holdem/bot"
+)
+
+var blinds = holdem.Config{SmallBlind: 1, BigBlind: 2}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		cfg    Config
+		agents func() []holdem.Agent
+		check  func(t *testing.T, stats []Stats)
+	}{
+		{
+			// mirrored cards cancel the luck of identical agents
+			name:   "duplicate mirror",
+			cfg:    Config{Game: blinds, Stack: 200, Hands: 500, Duplicate: true},
+			agents: func() []holdem.Agent { return []holdem.Agent{bot.CallingStation{}, bot.CallingStation{}} },
+			check: func(t *testing.T, stats []Stats) {
+				want := []Stats{{Hands: 1000}, {Hands: 1000}}
+				if diff := cmp.Diff(stats, want); diff != "" {
+					t.Errorf("want and got are different(-got +want): %s", diff)
+				}
+			},
+		},
+		{
+			name: "folding the button loses",
+			cfg:  Config{Game: blinds, Stack: 200, Hands: 1000, Duplicate: true, Seed: 1},
+			agents: func() []holdem.Agent {
+				folder := holdem.AgentFunc(func(v *holdem.View) holdem.Action { return v.CheckOrFold() })
+				return []holdem.Agent{folder, bot.CallingStation{}}
+			},
+			check: func(t *testing.T, stats []Stats) {
+				if s := stats[0]; s.BBPer100+s.CI95 >= 0 {
+					t.Errorf("want is a loss for the folder, but got %.1f ± %.1f bb/100", s.BBPer100, s.CI95)
+				}
+				if stats[0].Net != -stats[1].Net {
+					t.Errorf("want is opposite nets, but got %d and %d", stats[0].Net, stats[1].Net)
+				}
+			},
+		},
+		{
+			name: "zero sum with more players",
+			cfg:  Config{Game: holdem.Config{SmallBlind: 1, BigBlind: 2, Ante: 1}, Stack: 100, Hands: 300, Seed: 2},
+			agents: func() []holdem.Agent {
+				return []holdem.Agent{bot.NewTightAggressive(), bot.CallingStation{}, bot.NewRandom(rand.New(rand.NewSource(2)))}
+			},
+			check: func(t *testing.T, stats []Stats) {
+				total := 0
+				for _, s := range stats {
+					total += s.Net
+					if s.Hands != 300 {
+						t.Errorf("want is 300 hands, but got %d", s.Hands)
+					}
+				}
+				if total != 0 {
+					t.Errorf("want is 0 won in total, but got %d", total)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stats, err := Run(tt.cfg, tt.agents())
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, stats)
+		})
+	}
+}
+
+func TestRun_Reproducible(t *testing.T) {
+	t.Parallel()
+	run := func() []Stats {
+		agents := []holdem.Agent{bot.NewRandom(rand.New(rand.NewSource(3))), bot.NewTightAggressive()}
+		stats, err := Run(Config{Game: blinds, Stack: 200, Hands: 200, Seed: 3}, agents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+	if diff := cmp.Diff(run(), run()); diff != "" {
+		t.Errorf("the same seed gave different stats(-got +want): %s", diff)
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	t.Parallel()
+	if _, err := Run(Config{Game: blinds, Stack: 100, Hands: 1}, []holdem.Agent{bot.CallingStation{}}); err == nil {
+		t.Error("want error for one agent but got nil")
+	}
+	if _, err := Run(Config{Game: blinds, Hands: 1}, []holdem.Agent{bot.CallingStation{}, bot.CallingStation{}}); err == nil {
+		t.Error("want error for no stack but got nil")
+	}
+}
@@ -0,0 +1,268 @@
+package holdem
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/yuzuy/poker"
+)
+
+// State is a hand as of some point of its event log. States are values:
+// Reduce returns a new one rather than changing the one it is given.
+type State struct {
+	Config Config
+	Button int
+	// Deck holds the cards not dealt yet, burn cards included.
+	Deck   []poker.Card
+	Holes  []poker.PersonalHand
+	Board  poker.Board
+	Street Street
+	// Stacks, Bets and Folded are indexed by seat like in View.
+	Stacks []int
+	Bets   []int
+	Folded []bool
+	// Pot holds the chips put in and not paid out yet.
+	Pot     int
+	History []PlayerAction
+	// Shown holds the hole cards shown at the showdown by seat.
+	Shown []poker.PersonalHand
+	// Result is set once the hand is over.
+	Result *Result
+	// Events is the log the state was built from.
+	Events []Event
+
+	// game is the hand after the last action, and pending the events it
+	// emitted that the log has not reached yet.
+	game    *Game
+	pending []Event
+}
+
+// Reduce returns the state after e, starting from a nil state. Actions are
+// checked against the rules, and every other event must be the one the hand
+// produces next.
+func Reduce(s *State, e Event) (*State, error) {
+	if s == nil {
+		start, ok := e.(HandStarted)
+		if !ok {
+			return nil, fmt.Errorf("holdem: the log starts with %T, want HandStarted", e)
+		}
+		return start.state()
+	}
+
+	next := s.copy()
+	next.Events = append(s.Events[:len(s.Events):len(s.Events)], e)
+	if len(s.pending) > 0 {
+		if !reflect.DeepEqual(s.pending[0], e) {
+			return nil, fmt.Errorf("holdem: event %d does not follow from the hand", len(s.Events))
+		}
+		next.pending = s.pending[1:]
+		next.apply(e)
+		return next, nil
+	}
+
+	a, ok := e.(ActionTaken)
+	if !ok {
+		return nil, fmt.Errorf("holdem: event %d is %T, want ActionTaken", len(s.Events), e)
+	}
+	if s.game.ToAct() != a.Seat {
+		return nil, fmt.Errorf("holdem: seat %d acted out of turn", a.Seat)
+	}
+	var emitted []Event
+	next.game = s.game.clone(ObserverFunc(func(e Event) { emitted = append(emitted, e) }))
+	if err := next.game.Act(a.Action); err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(emitted[0], e) {
+		return nil, fmt.Errorf("holdem: event %d does not follow from the hand", len(s.Events))
+	}
+	next.pending = emitted[1:]
+	next.apply(e)
+	return next, nil
+}
+
+// Replay rebuilds the state after events.
+func Replay(events []Event) (*State, error) {
+	var s *State
+	for _, e := range events {
+		var err error
+		if s, err = Reduce(s, e); err != nil {
+			return nil, err
+		}
+	}
+	if s == nil {
+		return nil, errors.New("holdem: empty log")
+	}
+	return s, nil
+}
+
+func (e HandStarted) state() (*State, error) {
+	var emitted []Event
+	g, err := NewGame(e.Config, e.Stacks, e.Button, poker.NewDeck(e.Deck...),
+		ObserverFunc(func(e Event) { emitted = append(emitted, e) }))
+	if err != nil {
+		return nil, err
+	}
+	n := len(e.Stacks)
+	s := &State{
+		Config:  e.Config,
+		Button:  e.Button,
+		Deck:    append([]poker.Card(nil), e.Deck...),
+		Holes:   make([]poker.PersonalHand, n),
+		Stacks:  append([]int(nil), e.Stacks...),
+		Bets:    make([]int, n),
+		Folded:  make([]bool, n),
+		Events:  []Event{emitted[0]},
+		game:    g,
+		pending: emitted[1:],
+	}
+	for i, st := range e.Stacks {
+		s.Folded[i] = st == 0
+	}
+	return s, nil
+}
+
+func (s *State) copy() *State {
+	c := *s
+	c.Deck = append([]poker.Card(nil), s.Deck...)
+	c.Holes = append([]poker.PersonalHand(nil), s.Holes...)
+	c.Board = poker.Board{Cards: append([]poker.Card(nil), s.Board.Cards...)}
+	c.Stacks = append([]int(nil), s.Stacks...)
+	c.Bets = append([]int(nil), s.Bets...)
+	c.Folded = append([]bool(nil), s.Folded...)
+	c.History = append([]PlayerAction(nil), s.History...)
+	return &c
+}
+
+// apply updates the fields of s for e.
+func (s *State) apply(e Event) {
+	switch e := e.(type) {
+	case BlindsPosted:
+		for i, a := range e.Antes {
+			s.Stacks[i] -= a
+			s.Pot += a
+		}
+		for _, b := range []struct{ seat, amount int }{{e.SmallBlindSeat, e.SmallBlind}, {e.BigBlindSeat, e.BigBlind}} {
+			s.Stacks[b.seat] -= b.amount
+			s.Bets[b.seat] += b.amount
+			s.Pot += b.amount
+		}
+	case CardsDealt:
+		s.Holes[e.Seat] = e.Hole
+		s.Deck = remove(s.Deck, e.Hole.Cards)
+	case ActionTaken:
+		s.Stacks[e.Seat] -= e.Amount
+		s.Bets[e.Seat] += e.Amount
+		s.Pot += e.Amount
+		s.Folded[e.Seat] = s.Folded[e.Seat] || e.Action.Kind == Fold
+		s.History = append(s.History, e.PlayerAction)
+	case StreetDealt:
+		s.Deck = remove(s.Deck[1:], e.Cards)
+		s.Board.Cards = append(s.Board.Cards, e.Cards...)
+		s.Street = e.Street
+		s.Bets = make([]int, len(s.Bets))
+	case BetReturned:
+		s.Stacks[e.Seat] += e.Amount
+		s.Pot -= e.Amount
+		s.Bets = make([]int, len(s.Bets))
+	case Showdown:
+		s.Shown = e.Holes
+		s.Bets = make([]int, len(s.Bets))
+	case PotAwarded:
+		for i, w := range e.Won {
+			s.Stacks[i] += w
+		}
+		s.Pot -= e.Pot.Amount
+		s.Bets = make([]int, len(s.Bets))
+	case HandEnded:
+		s.Result = e.Result
+	}
+}
+
+func remove(cards, dealt []poker.Card) []poker.Card {
+	set := poker.NewCardSet(dealt...)
+	var left []poker.Card
+	for _, c := range cards {
+		if !set.Contains(c) {
+			left = append(left, c)
+		}
+	}
+	return left
+}
+
+// ToAct returns the seat to act next, or -1 when the next event is not an
+// action.
+func (s *State) ToAct() int {
+	if len(s.pending) > 0 {
+		return -1
+	}
+	return s.game.ToAct()
+}
+
+// Resume returns a game going on from s, telling observers of the events to
+// come. It fails unless the next event is an action.
+func (s *State) Resume(observers ...Observer) (*Game, error) {
+	if s.ToAct() < 0 {
+		return nil, errors.New("holdem: no action is due")
+	}
+	return s.game.clone(observers...), nil
+}
+
+// clone returns a copy of g telling observers of its events.
+func (g *Game) clone(observers ...Observer) *Game {
+	c := *g
+	c.deck = poker.NewDeck(g.deck.Cards...)
+	c.hole = append([]poker.PersonalHand(nil), g.hole...)
+	c.board = poker.Board{Cards: append([]poker.Card(nil), g.board.Cards...)}
+	c.stacks = append([]int(nil), g.stacks...)
+	c.start = append([]int(nil), g.start...)
+	c.bets = append([]int(nil), g.bets...)
+	c.total = append([]int(nil), g.total...)
+	c.folded = append([]bool(nil), g.folded...)
+	c.acted = append([]bool(nil), g.acted...)
+	c.canRaise = append([]bool(nil), g.canRaise...)
+	c.history = append([]PlayerAction(nil), g.history...)
+	c.observers = observers
+	return &c
+}
+
+// Log is the append-only record of the events of a hand, kept by observing
+// its game.
+type Log struct {
+	events []Event
+}
+
+func (l *Log) Observe(e Event) {
+	l.events = append(l.events, e)
+}
+
+func (l *Log) Len() int {
+	return len(l.events)
+}
+
+func (l *Log) Events() []Event {
+	return append([]Event(nil), l.events...)
+}
+
+// At rebuilds the state after the first n events.
+func (l *Log) At(n int) (*State, error) {
+	if n <= 0 || n > len(l.events) {
+		return nil, fmt.Errorf("holdem: no state after %d of %d events", n, len(l.events))
+	}
+	return Replay(l.events[:n])
+}
+
+// Rollback drops the events after the first n and returns the state there.
+// The hand goes on from it with the game of State.Resume observed by l, so
+// n must leave a player to act or the hand over. Otherwise l is unchanged.
+func (l *Log) Rollback(n int) (*State, error) {
+	s, err := l.At(n)
+	if err != nil {
+		return nil, err
+	}
+	if s.ToAct() < 0 && s.Result == nil {
+		return nil, fmt.Errorf("holdem: cannot roll back to event %d before the events of an action", n)
+	}
+	l.events = l.events[:n:n]
+	return s, nil
+}
@@ -0,0 +1,205 @@
+package holdem
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+)
+
+// playedLog returns the log of a heads up hand going to the showdown, with
+// the flop dealt by event 6.
+func playedLog(t *testing.T) (*Log, *Game) {
+	t.Helper()
+	log := &Log{}
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2}, []int{100, 100}, 1,
+		stackedDeck(t, "As Kd Ah Kc", "2c 7d 9h Th 3s"), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Call}, Action{Kind: Check})
+	mustAct(t, g, Action{Kind: Bet, Amount: 4}, Action{Kind: Call})
+	mustAct(t, g, Action{Kind: Check}, Action{Kind: Check})
+	mustAct(t, g, Action{Kind: Bet, Amount: 10}, Action{Kind: Call})
+	return log, g
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+	log, g := playedLog(t)
+	for n := 1; n <= log.Len(); n++ {
+		if _, err := log.At(n); err != nil {
+			t.Fatalf("At(%d) error: %v", n, err)
+		}
+	}
+
+	s, err := log.At(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Street != Flop || s.Pot != 4 || s.ToAct() != 0 {
+		t.Errorf("want is pot 4 and seat 0 to act on the flop, but got pot %d and seat %d on the %v", s.Pot, s.ToAct(), s.Street)
+	}
+	if diff := cmp.Diff(s.Stacks, []int{98, 98}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if diff := cmp.Diff(s.Board.Cards, mustParseCards(t, "2c 7d 9h")); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	// the stacked deck has a burn card and two streets left
+	if len(s.Deck) != 4 {
+		t.Errorf("want is 4 cards in the deck, but got %d", len(s.Deck))
+	}
+	if s, _ := log.At(6); s.ToAct() != -1 {
+		t.Errorf("want is nobody to act before the flop is dealt, but got seat %d", s.ToAct())
+	}
+
+	s, err = Replay(log.Events())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Result == nil {
+		t.Fatal("Result = nil after the hand")
+	}
+	if diff := cmp.Diff(s.Stacks, g.Result().Stacks); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if s.Pot != 0 {
+		t.Errorf("want is an empty pot after the hand, but got %d", s.Pot)
+	}
+}
+
+func TestReduce_Pure(t *testing.T) {
+	t.Parallel()
+	log, _ := playedLog(t)
+	events := log.Events()
+	s, err := Replay(events[:7])
+	if err != nil {
+		t.Fatal(err)
+	}
+	stacks, history := append([]int(nil), s.Stacks...), append([]PlayerAction(nil), s.History...)
+	for i := 0; i < 2; i++ {
+		next, err := Reduce(s, events[7])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(next.Stacks, []int{94, 98}); diff != "" {
+			t.Errorf("want and got are different(-got +want): %s", diff)
+		}
+	}
+	if diff := cmp.Diff(s.Stacks, stacks); diff != "" {
+		t.Errorf("Reduce changed its state(-got +want): %s", diff)
+	}
+	if diff := cmp.Diff(s.History, history); diff != "" {
+		t.Errorf("Reduce changed its state(-got +want): %s", diff)
+	}
+}
+
+func TestReduce_Error(t *testing.T) {
+	t.Parallel()
+	log, _ := playedLog(t)
+	events := log.Events()
+	tests := []struct {
+		name   string
+		events []Event
+	}{
+		{
+			name:   "no hand started",
+			events: events[1:],
+		},
+		{
+			name:   "out of turn",
+			events: append(events[:7:7], ActionTaken{PlayerAction: PlayerAction{Seat: 1, Street: Flop, Action: Action{Kind: Check}}}),
+		},
+		{
+			name:   "illegal action",
+			events: append(events[:7:7], ActionTaken{PlayerAction: PlayerAction{Seat: 0, Street: Flop, Action: Action{Kind: Bet, Amount: 1}}, Amount: 1}),
+		},
+		{
+			name:   "wrong amount",
+			events: append(events[:7:7], ActionTaken{PlayerAction: PlayerAction{Seat: 0, Street: Flop, Action: Action{Kind: Bet, Amount: 4}}, Amount: 3}),
+		},
+		{
+			name:   "other cards",
+			events: append(events[:6:6], StreetDealt{Street: Flop, Cards: mustParseCards(t, "2c 7d 9d")}),
+		},
+		{
+			name:   "after the end",
+			events: append(events[:len(events):len(events)], ActionTaken{}),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Replay(tt.events); err == nil {
+				t.Error("want error but got nil")
+			}
+		})
+	}
+}
+
+func TestLog_Rollback(t *testing.T) {
+	t.Parallel()
+	log, _ := playedLog(t)
+	played := log.Len()
+	// the flop is dealt after the call, as event 6
+	if _, err := log.Rollback(6); err == nil {
+		t.Error("Rollback(6): want error but got nil")
+	}
+	if log.Len() != played {
+		t.Fatalf("want is %d events after a failed rollback, but got %d", played, log.Len())
+	}
+	s, err := log.Rollback(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.Len() != 7 {
+		t.Fatalf("want is 7 events after the rollback, but got %d", log.Len())
+	}
+	g, err := s.Resume(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Bet, Amount: 50}, Action{Kind: Fold})
+	if diff := cmp.Diff(g.Result().Net, []int{2, -2}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+
+	replayed, err := Replay(log.Events())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(replayed.Stacks, []int{102, 98}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if _, err := (&State{Events: []Event{HandStarted{}}, pending: []Event{BlindsPosted{}}}).Resume(); err == nil {
+		t.Error("Resume with events pending: want error but got nil")
+	}
+	if _, err := log.At(0); err == nil {
+		t.Error("At(0): want error but got nil")
+	}
+}
+
+func TestReplay_SeededDeck(t *testing.T) {
+	t.Parallel()
+	log := &Log{}
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2}, []int{50, 50, 50}, 0, poker.NewSeededDeck(3), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Play([]Agent{AgentFunc((*View).CheckOrCall), AgentFunc((*View).CheckOrCall), AgentFunc((*View).CheckOrCall)})
+	s, err := Replay(log.Events())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(s.Board, g.Result().Board); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if diff := cmp.Diff(s.Stacks, g.Result().Stacks); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if len(s.Deck) != 52-6-8 {
+		t.Errorf("want is 38 cards in the deck, but got %d", len(s.Deck))
+	}
+}
@@ -0,0 +1,110 @@
+package holdem
+
+import (
+	"errors"
+
+	"github.com/yuzuy/poker"
+)
+
+// Spectator is the seat of someone watching a hand without playing in it.
+const Spectator = -1
+
+// Table is a hand as shown to one seat or to spectators. It holds no card
+// the viewer may not see, so it is safe to send as is.
+type Table struct {
+	// Seat is the viewer, or Spectator.
+	Seat   int
+	Config Config
+	Button int
+	// Holes holds the hole cards the viewer sees by seat, and is empty for
+	// the others.
+	Holes  []poker.PersonalHand
+	Board  poker.Board
+	Street Street
+	Stacks []int
+	Bets   []int
+	Folded []bool
+	Pot    int
+	// ToAct is the seat to act, or -1.
+	ToAct   int
+	History []PlayerAction
+	Result  *Result
+}
+
+// Table returns s as seen from seat: its own hole cards and those shown at
+// the showdown.
+func (s *State) Table(seat int) *Table {
+	t := s.table(seat)
+	for i := range t.Holes {
+		switch {
+		case i == seat:
+			t.Holes[i] = copyHole(s.Holes[i])
+		case i < len(s.Shown) && len(s.Shown[i].Cards) > 0:
+			t.Holes[i] = copyHole(s.Shown[i])
+		}
+	}
+	return t
+}
+
+// Broadcast returns the table delay events behind the end of the log with
+// every hole card shown, for spectators watching on a delay long enough
+// that the players cannot use it.
+func (l *Log) Broadcast(delay int) (*Table, error) {
+	if delay <= 0 {
+		return nil, errors.New("holdem: a broadcast needs a delay")
+	}
+	s, err := l.At(l.Len() - delay)
+	if err != nil {
+		return nil, err
+	}
+	t := s.table(Spectator)
+	for i, h := range s.Holes {
+		t.Holes[i] = copyHole(h)
+	}
+	return t, nil
+}
+
+func (s *State) table(seat int) *Table {
+	return &Table{
+		Seat:    seat,
+		Config:  s.Config,
+		Button:  s.Button,
+		Holes:   make([]poker.PersonalHand, len(s.Holes)),
+		Board:   poker.Board{Cards: append([]poker.Card(nil), s.Board.Cards...)},
+		Street:  s.Street,
+		Stacks:  append([]int(nil), s.Stacks...),
+		Bets:    append([]int(nil), s.Bets...),
+		Folded:  append([]bool(nil), s.Folded...),
+		Pot:     s.Pot,
+		ToAct:   s.ToAct(),
+		History: append([]PlayerAction(nil), s.History...),
+		Result:  s.Result,
+	}
+}
+
+func copyHole(h poker.PersonalHand) poker.PersonalHand {
+	return poker.PersonalHand{Cards: append([]poker.Card(nil), h.Cards...)}
+}
+
+// Redact returns e as seat, or Spectator, may see it: without the deck
+// order, and without the hole cards of other seats until the showdown.
+func Redact(e Event, seat int) Event {
+	switch e := e.(type) {
+	case HandStarted:
+		e.Deck = nil
+		return e
+	case CardsDealt:
+		if e.Seat != seat {
+			e.Hole = poker.PersonalHand{}
+		}
+		return e
+	default:
+		return e
+	}
+}
+
+// RedactedObserver returns an Observer passing the events of a hand to o as
+// seat, or Spectator, may see them.
+func RedactedObserver(o Observer, seat int) Observer {
+	return ObserverFunc(func(e Event) { o.Observe(Redact(e, seat)) })
+}
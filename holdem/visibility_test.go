@@ -0,0 +1,162 @@
+package holdem
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+)
+
+// foldedLog returns the log of a hand where seat 0 folds and seats 1 and 2,
+// holding As Ah and Kd Kc, check down to the showdown. The flop is dealt
+// by event 8.
+func foldedLog(t *testing.T, observers ...Observer) *Log {
+	t.Helper()
+	log := &Log{}
+	g, err := NewGame(Config{SmallBlind: 1, BigBlind: 2}, []int{100, 100, 100}, 0,
+		stackedDeck(t, "As Kd 2h Ah Kc 7s", "2c 7d 9h Th 3s"), append(observers, log)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAct(t, g, Action{Kind: Fold}, Action{Kind: Call}, Action{Kind: Check})
+	for i := 0; i < 6; i++ {
+		mustAct(t, g, Action{Kind: Check})
+	}
+	return log
+}
+
+func holes(t *testing.T, cards ...string) []poker.PersonalHand {
+	t.Helper()
+	hs := make([]poker.PersonalHand, len(cards))
+	for i, c := range cards {
+		if c != "" {
+			hs[i].Cards = mustParseCards(t, c)
+		}
+	}
+	return hs
+}
+
+func TestState_Table(t *testing.T) {
+	t.Parallel()
+	log := foldedLog(t)
+	during, err := log.At(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := log.At(log.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		s     *State
+		seat  int
+		holes []poker.PersonalHand
+	}{
+		{
+			name:  "own cards",
+			s:     during,
+			seat:  1,
+			holes: holes(t, "", "As Ah", ""),
+		},
+		{
+			name:  "spectator",
+			s:     during,
+			seat:  Spectator,
+			holes: holes(t, "", "", ""),
+		},
+		{
+			name:  "shown at the showdown",
+			s:     after,
+			seat:  1,
+			holes: holes(t, "", "As Ah", "Kd Kc"),
+		},
+		{
+			name:  "folded cards stay hidden",
+			s:     after,
+			seat:  Spectator,
+			holes: holes(t, "", "As Ah", "Kd Kc"),
+		},
+		{
+			name:  "folded player",
+			s:     after,
+			seat:  0,
+			holes: holes(t, "2h 7s", "As Ah", "Kd Kc"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.s.Table(tt.seat)
+			if diff := cmp.Diff(got.Holes, tt.holes); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+			if got.Seat != tt.seat || got.Pot != tt.s.Pot {
+				t.Errorf("want is seat %d and pot %d, but got %d and %d", tt.seat, tt.s.Pot, got.Seat, got.Pot)
+			}
+		})
+	}
+}
+
+func TestLog_Broadcast(t *testing.T) {
+	t.Parallel()
+	log := foldedLog(t)
+	got, err := log.Broadcast(log.Len() - 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got.Holes, holes(t, "2h 7s", "As Ah", "Kd Kc")); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if len(got.Board.Cards) != 0 || len(got.History) != 3 {
+		t.Errorf("want is 0 board cards and 3 actions broadcast, but got %d and %d", len(got.Board.Cards), len(got.History))
+	}
+	for _, delay := range []int{0, log.Len()} {
+		if _, err := log.Broadcast(delay); err == nil {
+			t.Errorf("Broadcast(%d): want error but got nil", delay)
+		}
+	}
+}
+
+func TestRedactedObserver(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		seat int
+		want []poker.PersonalHand
+	}{
+		{
+			name: "player",
+			seat: 2,
+			want: holes(t, "", "", "Kd Kc"),
+		},
+		{
+			name: "spectator",
+			seat: Spectator,
+			want: holes(t, "", "", ""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var events []Event
+			foldedLog(t, RedactedObserver(ObserverFunc(func(e Event) { events = append(events, e) }), tt.seat))
+			got := make([]poker.PersonalHand, 3)
+			for _, e := range events {
+				switch e := e.(type) {
+				case HandStarted:
+					if e.Deck != nil {
+						t.Errorf("the deck is sent to seat %d", tt.seat)
+					}
+				case CardsDealt:
+					got[e.Seat] = e.Hole
+				}
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("want and got are different(-got +want): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Package icm calculates tournament equity with the Independent Chip Model.
+package icm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+var ErrTooLarge = errors.New("icm: too many players for an exact calculation")
+
+// maxStates bounds the number of partial finishing orders tracked by the
+// exact calculations.
+const maxStates = 1 << 22
+
+type Model int
+
+const (
+	// Harville finishes players first with probability proportional to
+	// their stacks, then second among the rest, and so on.
+	Harville Model = iota + 1
+	// Weitzman eliminates players next with probability inversely
+	// proportional to their stacks.
+	Weitzman
+)
+
+func validate(stacks, payouts []float64) error {
+	if len(stacks) == 0 {
+		return errors.New("icm: no players")
+	}
+	if len(stacks) > 64 {
+		return ErrTooLarge
+	}
+	total := 0.0
+	for _, s := range stacks {
+		if s < 0 || math.IsNaN(s) || math.IsInf(s, 0) {
+			return fmt.Errorf("icm: invalid stack %v", s)
+		}
+		total += s
+	}
+	if total == 0 {
+		return errors.New("icm: no chips in play")
+	}
+	for _, p := range payouts {
+		if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+			return fmt.Errorf("icm: invalid payout %v", p)
+		}
+	}
+	return nil
+}
+
+// Equity returns the exact expected payout of each player under the
+// Harville model. payouts[0] is paid to the winner.
+func Equity(stacks, payouts []float64) ([]float64, error) {
+	if err := validate(stacks, payouts); err != nil {
+		return nil, err
+	}
+	n := len(stacks)
+	places := len(payouts)
+	if places > n {
+		places = n
+	}
+	states := 0.0
+	c := 1.0
+	for j := 0; j < places; j++ {
+		states += c
+		c = c * float64(n-j) / float64(j+1)
+	}
+	if states > maxStates {
+		return nil, ErrTooLarge
+	}
+
+	total := 0.0
+	for _, s := range stacks {
+		total += s
+	}
+	equity := make([]float64, n)
+	// level maps the set of players taking the places decided so far to the
+	// probability of that happening
+	level := map[uint64]float64{0: 1}
+	chips := map[uint64]float64{0: 0}
+	for place := 0; place < places; place++ {
+		next := map[uint64]float64{}
+		nextChips := map[uint64]float64{}
+		for placed, p := range level {
+			rest := total - chips[placed]
+			left := n - place
+			for i, s := range stacks {
+				bit := uint64(1) << uint(i)
+				if placed&bit != 0 {
+					continue
+				}
+				var q float64
+				if rest > 0 {
+					q = p * s / rest
+				} else {
+					q = p / float64(left)
+				}
+				if q == 0 {
+					continue
+				}
+				equity[i] += q * payouts[place]
+				next[placed|bit] += q
+				nextChips[placed|bit] = chips[placed] + s
+			}
+		}
+		level, chips = next, nextChips
+	}
+	return equity, nil
+}
+
+// MalmuthWeitzman returns the exact expected payout of each player under
+// the Weitzman elimination model.
+func MalmuthWeitzman(stacks, payouts []float64) ([]float64, error) {
+	if err := validate(stacks, payouts); err != nil {
+		return nil, err
+	}
+	n := len(stacks)
+	if n > 22 {
+		return nil, ErrTooLarge
+	}
+
+	equity := make([]float64, n)
+	// level maps the set of eliminated players to its probability
+	level := map[uint64]float64{0: 1}
+	for busted := 0; busted < n; busted++ {
+		place := n - busted - 1
+		next := map[uint64]float64{}
+		for out, p := range level {
+			weights := make([]float64, n)
+			zero := 0
+			for i, s := range stacks {
+				if out&(1<<uint(i)) == 0 && s == 0 {
+					zero++
+				}
+			}
+			sum := 0.0
+			for i, s := range stacks {
+				if out&(1<<uint(i)) != 0 {
+					continue
+				}
+				switch {
+				case zero > 0 && s == 0:
+					weights[i] = 1
+				case zero == 0:
+					weights[i] = 1 / s
+				}
+				sum += weights[i]
+			}
+			for i, w := range weights {
+				if w == 0 {
+					continue
+				}
+				q := p * w / sum
+				if place < len(payouts) {
+					equity[i] += q * payouts[place]
+				}
+				next[out|1<<uint(i)] += q
+			}
+		}
+		level = next
+	}
+	return equity, nil
+}
+
+// MonteCarlo estimates the expected payouts by sampling finishing orders
+// under model, which scales to fields too large for the exact calculations.
+func MonteCarlo(stacks, payouts []float64, model Model, iterations int, rnd *rand.Rand) ([]float64, error) {
+	if err := validate(stacks, payouts); err != nil {
+		return nil, err
+	}
+	if iterations <= 0 {
+		return nil, errors.New("icm: iterations must be positive")
+	}
+	if model != Harville && model != Weitzman {
+		return nil, fmt.Errorf("icm: unknown model %d", model)
+	}
+
+	n := len(stacks)
+	equity := make([]float64, n)
+	order := make([]int, n)
+	keys := make([]float64, n)
+	for it := 0; it < iterations; it++ {
+		// Racing exponential clocks picks players with probability
+		// proportional to their rates, one place at a time.
+		for i, s := range stacks {
+			order[i] = i
+			e := rnd.ExpFloat64()
+			switch model {
+			case Harville:
+				keys[i] = e / s
+			case Weitzman:
+				keys[i] = -e * s
+			}
+			if math.IsNaN(keys[i]) {
+				keys[i] = math.Inf(1)
+			}
+		}
+		rnd.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		sort.SliceStable(order, func(i, j int) bool {
+			return keys[order[i]] < keys[order[j]]
+		})
+		for place, i := range order {
+			if place < len(payouts) {
+				equity[i] += payouts[place]
+			}
+		}
+	}
+	for i := range equity {
+		equity[i] /= float64(iterations)
+	}
+	return equity, nil
+}
@@ -0,0 +1,177 @@
+package icm
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func near(a, b []float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEquity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stacks  []float64
+		payouts []float64
+		want    []float64
+	}{
+		{
+			name:    "heads up",
+			stacks:  []float64{3000, 1000},
+			payouts: []float64{70, 30},
+			want:    []float64{60, 40},
+		},
+		{
+			name:    "three players",
+			stacks:  []float64{5000, 3000, 2000},
+			payouts: []float64{50, 30, 20},
+			want: []float64{
+				50*0.5 + 30*(0.3*5/7+0.2*5/8) + 20*(1-0.5-(0.3*5/7+0.2*5/8)),
+				50*0.3 + 30*(0.5*3/5+0.2*3/8) + 20*(1-0.3-(0.5*3/5+0.2*3/8)),
+				50*0.2 + 30*(0.5*2/5+0.3*2/7) + 20*(1-0.2-(0.5*2/5+0.3*2/7)),
+			},
+		},
+		{
+			name:    "winner takes all",
+			stacks:  []float64{1, 2, 7},
+			payouts: []float64{100},
+			want:    []float64{10, 20, 70},
+		},
+		{
+			name:    "busted player",
+			stacks:  []float64{5000, 5000, 0},
+			payouts: []float64{50, 30, 20},
+			want:    []float64{40, 40, 20},
+		},
+		{
+			name:    "more payouts than players",
+			stacks:  []float64{1, 1},
+			payouts: []float64{50, 30, 20},
+			want:    []float64{40, 40},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Equity(tt.stacks, tt.payouts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(got, tt.want, 1e-9) {
+				t.Fatalf("want is %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEquity_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Equity(nil, []float64{1}); err == nil {
+		t.Fatalf("no players were accepted")
+	}
+	if _, err := Equity([]float64{-1, 2}, []float64{1}); err == nil {
+		t.Fatalf("negative stack was accepted")
+	}
+	if _, err := Equity([]float64{0, 0}, []float64{1}); err == nil {
+		t.Fatalf("no chips were accepted")
+	}
+	if _, err := Equity([]float64{1, 2}, []float64{-1}); err == nil {
+		t.Fatalf("negative payout was accepted")
+	}
+
+	stacks := make([]float64, 60)
+	payouts := make([]float64, 30)
+	for i := range stacks {
+		stacks[i] = 1
+	}
+	if _, err := Equity(stacks, payouts); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("want is %v, but got %v", ErrTooLarge, err)
+	}
+}
+
+func TestMalmuthWeitzman(t *testing.T) {
+	t.Parallel()
+
+	got, err := MalmuthWeitzman([]float64{3000, 1000}, []float64{70, 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{60, 40}; !near(got, want, 1e-9) {
+		t.Fatalf("want is %v, but got %v", want, got)
+	}
+
+	got, err = MalmuthWeitzman([]float64{2, 2, 2, 2}, []float64{50, 30, 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{25, 25, 25, 25}; !near(got, want, 1e-9) {
+		t.Fatalf("want is %v, but got %v", want, got)
+	}
+
+	// the short stack is eliminated first more often than Harville predicts
+	stacks, payouts := []float64{6000, 3000, 1000}, []float64{50, 30, 20}
+	mw, err := MalmuthWeitzman(stacks, payouts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := Equity(stacks, payouts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := mw[0] + mw[1] + mw[2]; math.Abs(sum-100) > 1e-9 {
+		t.Fatalf("want is equities summing to 1, but got %v", sum)
+	}
+	if mw[2] >= h[2] {
+		t.Fatalf("want is less than %v for the short stack, but got %v", h[2], mw[2])
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	t.Parallel()
+
+	stacks := []float64{5000, 3000, 2000, 1500, 500}
+	payouts := []float64{50, 30, 20}
+	tests := []struct {
+		model Model
+		exact func([]float64, []float64) ([]float64, error)
+	}{
+		{model: Harville, exact: Equity},
+		{model: Weitzman, exact: MalmuthWeitzman},
+	}
+	for _, tt := range tests {
+		want, err := tt.exact(stacks, payouts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := MonteCarlo(stacks, payouts, tt.model, 200000, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !near(got, want, 0.3) {
+			t.Fatalf("model %d: want is %v, but got %v", tt.model, want, got)
+		}
+	}
+
+	if _, err := MonteCarlo(stacks, payouts, Harville, 0, rand.New(rand.NewSource(1))); err == nil {
+		t.Fatalf("zero iterations were accepted")
+	}
+	if _, err := MonteCarlo(stacks, payouts, Model(0), 1, rand.New(rand.NewSource(1))); err == nil {
+		t.Fatalf("unknown model was accepted")
+	}
+}
@@ -0,0 +1,576 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Indexer maps hands dealt over several rounds, such as hole cards followed
+// by the flop, turn and river, onto dense integer indices and back. Raw
+// indices tell every set of cards apart, while isomorphic indices give hands
+// that only differ by relabelling suits the same index.
+type Indexer struct {
+	rounds   []int
+	configs  [][]suitConfig
+	byVector []map[[4]suitVector]int
+	sizes    []uint64
+	raw      []uint64
+}
+
+const maxIndexerRounds = 8
+
+// suitVector holds how many cards of one suit were dealt in each round.
+type suitVector [maxIndexerRounds]uint8
+
+type suitConfig struct {
+	vectors [4]suitVector
+	offset  uint64
+	size    uint64
+}
+
+func NewHoldemIndexer() *Indexer {
+	x, err := NewIndexer(2, 3, 1, 1)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+func NewIndexer(rounds ...int) (*Indexer, error) {
+	if len(rounds) == 0 || len(rounds) > maxIndexerRounds {
+		return nil, fmt.Errorf("poker: need 1 to %d rounds, got %d", maxIndexerRounds, len(rounds))
+	}
+	total := 0
+	for _, n := range rounds {
+		if n <= 0 {
+			return nil, fmt.Errorf("poker: invalid round size %d", n)
+		}
+		total += n
+	}
+	if total > 52 {
+		return nil, fmt.Errorf("poker: %d cards do not fit in a deck", total)
+	}
+
+	x := &Indexer{rounds: append([]int(nil), rounds...)}
+	dealt := 0
+	var raw uint64 = 1
+	for r := range rounds {
+		c, ok := binomial(uint64(52-dealt), uint64(rounds[r]))
+		if !ok || overflowMul(raw, c) {
+			return nil, errors.New("poker: too many hands to index")
+		}
+		raw *= c
+		dealt += rounds[r]
+		x.raw = append(x.raw, raw)
+
+		configs, size, err := x.enumerateConfigs(r)
+		if err != nil {
+			return nil, err
+		}
+		x.configs = append(x.configs, configs)
+		byVector := map[[4]suitVector]int{}
+		for i, c := range configs {
+			byVector[c.vectors] = i
+		}
+		x.byVector = append(x.byVector, byVector)
+		x.sizes = append(x.sizes, size)
+	}
+	return x, nil
+}
+
+func (x *Indexer) Rounds() int {
+	return len(x.rounds)
+}
+
+// Size returns the number of isomorphic indices after round.
+func (x *Indexer) Size(round int) uint64 {
+	return x.sizes[round]
+}
+
+// RawSize returns the number of raw indices after round.
+func (x *Indexer) RawSize(round int) uint64 {
+	return x.raw[round]
+}
+
+// enumerateConfigs lists every way the cards dealt up to round can be spread
+// over the suits, with suits ordered by their vectors, largest first.
+func (x *Indexer) enumerateConfigs(round int) ([]suitConfig, uint64, error) {
+	var configs []suitConfig
+	var remaining [maxIndexerRounds]int
+	copy(remaining[:], x.rounds[:round+1])
+
+	var vectors [4]suitVector
+	var assign func(s int) error
+	assign = func(s int) error {
+		if s == 4 {
+			for i := 0; i <= round; i++ {
+				if remaining[i] != 0 {
+					return nil
+				}
+			}
+			size, ok := x.configSize(vectors, round)
+			if !ok {
+				return errors.New("poker: too many hands to index")
+			}
+			configs = append(configs, suitConfig{vectors: vectors, size: size})
+			return nil
+		}
+
+		var v suitVector
+		var fill func(i, cards int) error
+		fill = func(i, cards int) error {
+			if i > round {
+				if s > 0 && vectorLess(vectors[s-1], v) {
+					return nil
+				}
+				vectors[s] = v
+				return assign(s + 1)
+			}
+			for n := 0; n <= remaining[i] && cards+n <= 13; n++ {
+				v[i] = uint8(n)
+				remaining[i] -= n
+				err := fill(i+1, cards+n)
+				remaining[i] += n
+				if err != nil {
+					return err
+				}
+			}
+			v[i] = 0
+			return nil
+		}
+		return fill(0, 0)
+	}
+	if err := assign(0); err != nil {
+		return nil, 0, err
+	}
+
+	var offset uint64
+	for i := range configs {
+		configs[i].offset = offset
+		if overflowAdd(offset, configs[i].size) {
+			return nil, 0, errors.New("poker: too many hands to index")
+		}
+		offset += configs[i].size
+	}
+	return configs, offset, nil
+}
+
+func (x *Indexer) configSize(vectors [4]suitVector, round int) (uint64, bool) {
+	var size uint64 = 1
+	for _, g := range groupVectors(vectors) {
+		n, ok := suitHandCount(g.vector, round)
+		if !ok {
+			return 0, false
+		}
+		c, ok := multisetCount(n, uint64(g.count))
+		if !ok || overflowMul(size, c) {
+			return 0, false
+		}
+		size *= c
+	}
+	return size, true
+}
+
+type vectorGroup struct {
+	vector suitVector
+	start  int
+	count  int
+}
+
+// groupVectors splits suits that are already in order into runs sharing the
+// same vector.
+func groupVectors(vectors [4]suitVector) []vectorGroup {
+	var groups []vectorGroup
+	for s := 0; s < 4; s++ {
+		if len(groups) > 0 && groups[len(groups)-1].vector == vectors[s] {
+			groups[len(groups)-1].count++
+			continue
+		}
+		groups = append(groups, vectorGroup{vector: vectors[s], start: s, count: 1})
+	}
+	return groups
+}
+
+func vectorLess(a, b suitVector) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// suitHandCount returns how many ways the ranks of one suit can be dealt
+// following v.
+func suitHandCount(v suitVector, round int) (uint64, bool) {
+	var n uint64 = 1
+	used := 0
+	for i := 0; i <= round; i++ {
+		c, ok := binomial(uint64(13-used), uint64(v[i]))
+		if !ok || overflowMul(n, c) {
+			return 0, false
+		}
+		n *= c
+		used += int(v[i])
+	}
+	return n, true
+}
+
+func multisetCount(n, k uint64) (uint64, bool) {
+	if k == 0 {
+		return 1, true
+	}
+	return binomial(n+k-1, k)
+}
+
+func (x *Indexer) round(cards []Card) (int, error) {
+	total := 0
+	for r, n := range x.rounds {
+		total += n
+		if total == len(cards) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("poker: %d cards do not complete a round", len(cards))
+}
+
+func (x *Indexer) checkCards(cards []Card) (int, error) {
+	round, err := x.round(cards)
+	if err != nil {
+		return 0, err
+	}
+	if NewCardSet(cards...).Len() != len(cards) {
+		return 0, fmt.Errorf("poker: invalid or duplicated card in %v", cards)
+	}
+	return round, nil
+}
+
+// Index returns the isomorphic index of cards, which are given in dealing
+// order: the cards of the first round, then the second round and so on.
+func (x *Indexer) Index(cards []Card) (uint64, error) {
+	round, err := x.checkCards(cards)
+	if err != nil {
+		return 0, err
+	}
+
+	type suitHand struct {
+		vector suitVector
+		index  uint64
+	}
+	var hands [4]suitHand
+	var masks [4][maxIndexerRounds]uint16
+	pos := 0
+	for r := 0; r <= round; r++ {
+		for _, c := range cards[pos : pos+x.rounds[r]] {
+			s := c.Suit.index()
+			hands[s].vector[r]++
+			masks[s][r] |= rankBit(c.Rank)
+		}
+		pos += x.rounds[r]
+	}
+	for s := range hands {
+		hands[s].index = suitHandIndex(masks[s][:round+1])
+	}
+
+	sort.Slice(hands[:], func(i, j int) bool {
+		if hands[i].vector != hands[j].vector {
+			return vectorLess(hands[j].vector, hands[i].vector)
+		}
+		return hands[i].index > hands[j].index
+	})
+	var vectors [4]suitVector
+	for s := range hands {
+		vectors[s] = hands[s].vector
+	}
+
+	config := x.findConfig(round, vectors)
+	var index uint64
+	for _, g := range groupVectors(vectors) {
+		n, _ := suitHandCount(g.vector, round)
+		size, _ := multisetCount(n, uint64(g.count))
+		var ms uint64
+		for j := 0; j < g.count; j++ {
+			c, _ := binomial(hands[g.start+j].index+uint64(g.count-1-j), uint64(g.count-j))
+			ms += c
+		}
+		index = index*size + ms
+	}
+	return config.offset + index, nil
+}
+
+// Unindex returns the canonical cards of the hand with the isomorphic index
+// after round, in dealing order.
+func (x *Indexer) Unindex(round int, index uint64) ([]Card, error) {
+	if round < 0 || round >= len(x.rounds) || index >= x.sizes[round] {
+		return nil, fmt.Errorf("poker: index %d out of range for round %d", index, round)
+	}
+	configs := x.configs[round]
+	i := sort.Search(len(configs), func(i int) bool {
+		return configs[i].offset+configs[i].size > index
+	})
+	config := configs[i]
+	rest := index - config.offset
+
+	groups := groupVectors(config.vectors)
+	var handIndices [4]uint64
+	for gi := len(groups) - 1; gi >= 0; gi-- {
+		g := groups[gi]
+		n, _ := suitHandCount(g.vector, round)
+		size, _ := multisetCount(n, uint64(g.count))
+		ms := rest % size
+		rest /= size
+
+		z := colexUnrank(ms, g.count)
+		for j := 0; j < g.count; j++ {
+			// z is ascending, suits in a group are stored descending
+			handIndices[g.start+g.count-1-j] = z[j] - uint64(j)
+		}
+	}
+
+	var byRound [maxIndexerRounds][]Card
+	for s := 0; s < 4; s++ {
+		masks := suitHandUnindex(config.vectors[s], round, handIndices[s])
+		for r := 0; r <= round; r++ {
+			for m := masks[r]; m != 0; m &= m - 1 {
+				byRound[r] = append(byRound[r], Card{Suit: suits[s], Rank: Deuce + CardRank(bits.TrailingZeros16(m))})
+			}
+		}
+	}
+	var cards []Card
+	for r := 0; r <= round; r++ {
+		cards = append(cards, byRound[r]...)
+	}
+	return cards, nil
+}
+
+func (x *Indexer) findConfig(round int, vectors [4]suitVector) suitConfig {
+	return x.configs[round][x.byVector[round][vectors]]
+}
+
+// suitHandIndex ranks the ranks of one suit dealt in each round. Each round
+// is ranked among the ranks still unused by the previous rounds.
+func suitHandIndex(masks []uint16) uint64 {
+	var index uint64
+	var used uint16
+	for _, m := range masks {
+		free := 13 - bits.OnesCount16(used)
+		size, _ := binomial(uint64(free), uint64(bits.OnesCount16(m)))
+		index = index*size + colexRank(compress(m, used))
+		used |= m
+	}
+	return index
+}
+
+func suitHandUnindex(v suitVector, round int, index uint64) [maxIndexerRounds]uint16 {
+	var sizes [maxIndexerRounds]uint64
+	var local [maxIndexerRounds]uint64
+	used := 0
+	for r := 0; r <= round; r++ {
+		sizes[r], _ = binomial(uint64(13-used), uint64(v[r]))
+		used += int(v[r])
+	}
+	for r := round; r >= 0; r-- {
+		local[r] = index % sizes[r]
+		index /= sizes[r]
+	}
+
+	var masks [maxIndexerRounds]uint16
+	var usedMask uint16
+	for r := 0; r <= round; r++ {
+		var compressed uint16
+		for _, p := range colexUnrank(local[r], int(v[r])) {
+			compressed |= 1 << uint(p)
+		}
+		masks[r] = expand(compressed, usedMask)
+		usedMask |= masks[r]
+	}
+	return masks
+}
+
+// compress removes the bits of used from m, shifting the higher bits down.
+func compress(m, used uint16) uint16 {
+	var out uint16
+	j := uint(0)
+	for i := uint(0); i < 16; i++ {
+		if used>>i&1 == 1 {
+			continue
+		}
+		out |= (m >> i & 1) << j
+		j++
+	}
+	return out
+}
+
+// expand is the inverse of compress.
+func expand(m, used uint16) uint16 {
+	var out uint16
+	j := uint(0)
+	for i := uint(0); i < 16; i++ {
+		if used>>i&1 == 1 {
+			continue
+		}
+		out |= (m >> j & 1) << i
+		j++
+	}
+	return out
+}
+
+func colexRank(m uint16) uint64 {
+	var index uint64
+	k := uint64(1)
+	for ; m != 0; m &= m - 1 {
+		c, _ := binomial(uint64(bits.TrailingZeros16(m)), k)
+		index += c
+		k++
+	}
+	return index
+}
+
+// colexUnrank returns the ascending positions of the k-subset with index.
+func colexUnrank(index uint64, k int) []uint64 {
+	positions := make([]uint64, k)
+	for j := k; j >= 1; j-- {
+		// find the largest p with C(p, j) <= index
+		lo, hi := uint64(j-1), uint64(j)
+		for {
+			c, ok := binomial(hi, uint64(j))
+			if !ok || c > index {
+				break
+			}
+			lo, hi = hi, hi*2
+		}
+		for lo+1 < hi {
+			mid := lo + (hi-lo)/2
+			if c, ok := binomial(mid, uint64(j)); ok && c <= index {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		c, _ := binomial(lo, uint64(j))
+		index -= c
+		positions[j-1] = lo
+	}
+	return positions
+}
+
+func (x *Indexer) RawIndex(cards []Card) (uint64, error) {
+	round, err := x.checkCards(cards)
+	if err != nil {
+		return 0, err
+	}
+	var index uint64
+	var used uint64
+	pos := 0
+	for r := 0; r <= round; r++ {
+		var m uint64
+		for _, c := range cards[pos : pos+x.rounds[r]] {
+			m |= 1 << uint(c.index())
+		}
+		size, _ := binomial(uint64(52-bits.OnesCount64(used)), uint64(x.rounds[r]))
+		index = index*size + colexRank64(compress64(m, used))
+		used |= m
+		pos += x.rounds[r]
+	}
+	return index, nil
+}
+
+func (x *Indexer) RawUnindex(round int, index uint64) ([]Card, error) {
+	if round < 0 || round >= len(x.rounds) || index >= x.raw[round] {
+		return nil, fmt.Errorf("poker: raw index %d out of range for round %d", index, round)
+	}
+	local := make([]uint64, round+1)
+	dealt := 0
+	sizes := make([]uint64, round+1)
+	for r := 0; r <= round; r++ {
+		sizes[r], _ = binomial(uint64(52-dealt), uint64(x.rounds[r]))
+		dealt += x.rounds[r]
+	}
+	for r := round; r >= 0; r-- {
+		local[r] = index % sizes[r]
+		index /= sizes[r]
+	}
+
+	var cards []Card
+	var used uint64
+	for r := 0; r <= round; r++ {
+		var compressed uint64
+		for _, p := range colexUnrank(local[r], x.rounds[r]) {
+			compressed |= 1 << uint(p)
+		}
+		m := expand64(compressed, used)
+		for b := m; b != 0; b &= b - 1 {
+			cards = append(cards, cardFromIndex(bits.TrailingZeros64(b)))
+		}
+		used |= m
+	}
+	return cards, nil
+}
+
+func compress64(m, used uint64) uint64 {
+	var out uint64
+	j := uint(0)
+	for i := uint(0); i < 52; i++ {
+		if used>>i&1 == 1 {
+			continue
+		}
+		out |= (m >> i & 1) << j
+		j++
+	}
+	return out
+}
+
+func expand64(m, used uint64) uint64 {
+	var out uint64
+	j := uint(0)
+	for i := uint(0); i < 52; i++ {
+		if used>>i&1 == 1 {
+			continue
+		}
+		out |= (m >> j & 1) << i
+		j++
+	}
+	return out
+}
+
+func colexRank64(m uint64) uint64 {
+	var index uint64
+	k := uint64(1)
+	for ; m != 0; m &= m - 1 {
+		c, _ := binomial(uint64(bits.TrailingZeros64(m)), k)
+		index += c
+		k++
+	}
+	return index
+}
+
+// binomial returns n choose k and whether it fits in a uint64.
+func binomial(n, k uint64) (uint64, bool) {
+	if k > n {
+		return 0, true
+	}
+	if k > n-k {
+		k = n - k
+	}
+	var c uint64 = 1
+	for i := uint64(1); i <= k; i++ {
+		hi, lo := bits.Mul64(c, n-k+i)
+		if hi >= i {
+			return 0, false
+		}
+		c, _ = bits.Div64(hi, lo, i)
+	}
+	return c, true
+}
+
+func overflowMul(a, b uint64) bool {
+	hi, _ := bits.Mul64(a, b)
+	return hi != 0
+}
+
+func overflowAdd(a, b uint64) bool {
+	_, carry := bits.Add64(a, b, 0)
+	return carry != 0
+}
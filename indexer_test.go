@@ -0,0 +1,156 @@
+package poker
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIndexer_Size(t *testing.T) {
+	t.Parallel()
+
+	x := NewHoldemIndexer()
+	wantIso := []uint64{169, 1286792, 55190538, 2428287420}
+	wantRaw := []uint64{1326, 1326 * 19600, 1326 * 19600 * 47, 1326 * 19600 * 47 * 46}
+	for r := 0; r < x.Rounds(); r++ {
+		if got := x.Size(r); got != wantIso[r] {
+			t.Fatalf("round %d: want %d isomorphic hands, but got %d", r, wantIso[r], got)
+		}
+		if got := x.RawSize(r); got != wantRaw[r] {
+			t.Fatalf("round %d: want %d raw hands, but got %d", r, wantRaw[r], got)
+		}
+	}
+}
+
+func TestNewIndexer_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rounds []int
+	}{
+		{name: "no rounds", rounds: nil},
+		{name: "empty round", rounds: []int{2, 0}},
+		{name: "more than a deck", rounds: []int{50, 3}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := NewIndexer(tt.rounds...); err == nil {
+				t.Fatalf("rounds %v were accepted", tt.rounds)
+			}
+		})
+	}
+}
+
+func TestIndexer_Preflop(t *testing.T) {
+	t.Parallel()
+
+	x := NewHoldemIndexer()
+	classes := map[uint64]HandClass{}
+	eachCombination(FullCardSet.Cards(), 2, func(cs CardSet) {
+		hole := cs.PersonalHand()
+		i, err := x.Index(hole.Cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i >= x.Size(0) {
+			t.Fatalf("index %d out of range", i)
+		}
+		c, _ := hole.Class()
+		if prev, ok := classes[i]; ok && prev != c {
+			t.Fatalf("%v and %v share index %d", prev, c, i)
+		}
+		classes[i] = c
+	})
+	if len(classes) != NumHandClasses {
+		t.Fatalf("want %d indices, but got %d", NumHandClasses, len(classes))
+	}
+	for i := uint64(0); i < x.Size(0); i++ {
+		cards, err := x.Unindex(0, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := x.Index(cards); err != nil || got != i {
+			t.Fatalf("want index %d for %v, but got %d (%v)", i, cards, got, err)
+		}
+	}
+}
+
+func TestIndexer_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	x := NewHoldemIndexer()
+	rnd := rand.New(rand.NewSource(1))
+	for r := 0; r < x.Rounds(); r++ {
+		for n := 0; n < 2000; n++ {
+			i := uint64(rnd.Int63n(int64(x.Size(r))))
+			cards, err := x.Unindex(r, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := x.Index(cards); err != nil || got != i {
+				t.Fatalf("round %d: want index %d for %v, but got %d (%v)", r, i, cards, got, err)
+			}
+
+			raw := uint64(rnd.Int63n(int64(x.RawSize(r))))
+			cards, err = x.RawUnindex(r, raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := x.RawIndex(cards); err != nil || got != raw {
+				t.Fatalf("round %d: want raw index %d for %v, but got %d (%v)", r, raw, cards, got, err)
+			}
+		}
+	}
+}
+
+func TestIndexer_Isomorphism(t *testing.T) {
+	t.Parallel()
+
+	x := NewHoldemIndexer()
+	rnd := rand.New(rand.NewSource(2))
+	for n := 0; n < 5000; n++ {
+		deck := FullCardSet.Cards()
+		rnd.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+		cards := deck[:2+3+rnd.Intn(3)]
+		i, err := x.Index(cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := suitPermutations[rnd.Intn(len(suitPermutations))]
+		if got, _ := x.Index(m.Cards(cards)); got != i {
+			t.Fatalf("%v and %v have indices %d and %d", cards, m.Cards(cards), i, got)
+		}
+
+		canonical, err := x.Unindex(len(cards)-4, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, _ := Canonicalize(PersonalHand{Cards: cards[:2]}, Board{Cards: cards[2:]})
+		got, _, _ := Canonicalize(PersonalHand{Cards: canonical[:2]}, Board{Cards: canonical[2:]})
+		if got != want {
+			t.Fatalf("%v was unindexed into %v", cards, canonical)
+		}
+	}
+}
+
+func TestIndexer_Errors(t *testing.T) {
+	t.Parallel()
+
+	x := NewHoldemIndexer()
+	if _, err := x.Index(mustCards(t, "As Ks Qs")); err == nil {
+		t.Fatalf("incomplete round was accepted")
+	}
+	if _, err := x.Index(mustCards(t, "As As")); err == nil {
+		t.Fatalf("duplicated card was accepted")
+	}
+	if _, err := x.Unindex(0, 169); err == nil {
+		t.Fatalf("out of range index was accepted")
+	}
+	if _, err := x.RawUnindex(4, 0); err == nil {
+		t.Fatalf("out of range round was accepted")
+	}
+}
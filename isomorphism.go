@@ -0,0 +1,92 @@
+package poker
+
+import "fmt"
+
+// SuitMap relabels suits: a card of suit s becomes a card of suit m[i],
+// where i is the position of s in Spade, Club, Diamond, Heart order.
+type SuitMap [4]Suit
+
+var IdentitySuitMap = SuitMap{Spade, Club, Diamond, Heart}
+
+var suitPermutations = func() []SuitMap {
+	var perms []SuitMap
+	var permute func(m SuitMap, i int)
+	permute = func(m SuitMap, i int) {
+		if i == len(m) {
+			perms = append(perms, m)
+			return
+		}
+		for j := i; j < len(m); j++ {
+			m[i], m[j] = m[j], m[i]
+			permute(m, i+1)
+			m[i], m[j] = m[j], m[i]
+		}
+	}
+	permute(IdentitySuitMap, 0)
+	return perms
+}()
+
+func (m SuitMap) Card(c Card) Card {
+	return Card{Suit: m[c.Suit.index()], Rank: c.Rank}
+}
+
+func (m SuitMap) Cards(cards []Card) []Card {
+	mapped := make([]Card, len(cards))
+	for i, c := range cards {
+		mapped[i] = m.Card(c)
+	}
+	return mapped
+}
+
+func (m SuitMap) CardSet(cs CardSet) CardSet {
+	var mapped CardSet
+	for s := 0; s < 4; s++ {
+		mapped |= CardSet(cs.suitMask(s)) << (uint(m[s].index()) * 16)
+	}
+	return mapped
+}
+
+func (m SuitMap) Inverse() SuitMap {
+	var inv SuitMap
+	for i, s := range m {
+		inv[s.index()] = suits[i]
+	}
+	return inv
+}
+
+// CanonicalSpot is the representative of every holding and board that are
+// the same up to relabelling the suits. It is comparable and can be used as
+// a map key.
+type CanonicalSpot struct {
+	HoleCards  CardSet
+	BoardCards CardSet
+}
+
+func (s CanonicalSpot) PersonalHand() PersonalHand {
+	return comboHand(s.HoleCards)
+}
+
+func (s CanonicalSpot) Board() Board {
+	return s.BoardCards.Board()
+}
+
+// Canonicalize returns the canonical spot of hole on board together with the
+// suit map that turns the original cards into the canonical ones. The
+// inverse of the map turns results computed on the canonical spot back into
+// the original suits.
+func Canonicalize(hole PersonalHand, board Board) (CanonicalSpot, SuitMap, error) {
+	h, b := hole.CardSet(), board.CardSet()
+	if h.Len() != len(hole.Cards) || b.Len() != len(board.Cards) || h.Overlaps(b) {
+		return CanonicalSpot{}, SuitMap{}, fmt.Errorf("poker: invalid spot %v on %v", hole.Cards, board.Cards)
+	}
+
+	var best CanonicalSpot
+	var bestMap SuitMap
+	for i, m := range suitPermutations {
+		s := CanonicalSpot{HoleCards: m.CardSet(h), BoardCards: m.CardSet(b)}
+		if i == 0 || s.HoleCards < best.HoleCards || s.HoleCards == best.HoleCards && s.BoardCards < best.BoardCards {
+			best, bestMap = s, m
+		}
+	}
+	return best, bestMap, nil
+}
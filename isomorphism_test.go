@@ -0,0 +1,115 @@
+package poker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		hole, hole2   string
+		board, board2 string
+		wantSame      bool
+	}{
+		{name: "suits swapped", hole: "Ah Kh", board: "2c 7c 9d", hole2: "As Ks", board2: "2d 7d 9h", wantSame: true},
+		{name: "flush draw against no flush draw", hole: "Ah Kh", board: "2h 7c 9d", hole2: "Ah Kh", board2: "2c 7c 9d", wantSame: false},
+		{name: "preflop", hole: "Ac Kd", hole2: "Ah Ks", wantSame: true},
+		{name: "suited against offsuit", hole: "Ac Kc", hole2: "Ah Ks", wantSame: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, _, err := Canonicalize(PersonalHand{Cards: mustCards(t, tt.hole)}, Board{Cards: mustCards(t, tt.board)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _, err := Canonicalize(PersonalHand{Cards: mustCards(t, tt.hole2)}, Board{Cards: mustCards(t, tt.board2)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (a == b) != tt.wantSame {
+				t.Fatalf("canonical spots %v and %v", a, b)
+			}
+		})
+	}
+}
+
+func TestCanonicalize_MapBack(t *testing.T) {
+	t.Parallel()
+
+	hole := PersonalHand{Cards: mustCards(t, "Ah Kh")}
+	board := Board{Cards: mustCards(t, "2c 7c 9d")}
+	spot, m, err := Canonicalize(hole, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.CardSet(hole.CardSet()); got != spot.HoleCards {
+		t.Fatalf("want %v, but got %v", spot.HoleCards, got)
+	}
+	inv := m.Inverse()
+	if got := inv.CardSet(spot.HoleCards); got != hole.CardSet() {
+		t.Fatalf("want %v, but got %v", hole.CardSet(), got)
+	}
+	if diff := cmp.Diff(NewCardSet(inv.Cards(spot.Board().Cards)...).Cards(), board.CardSet().Cards()); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+	if got := inv.CardSet(spot.BoardCards); got != board.CardSet() {
+		t.Fatalf("want %v, but got %v", board.CardSet(), got)
+	}
+	if got := spot.PersonalHand().CardSet(); got != spot.HoleCards {
+		t.Fatalf("want %v, but got %v", spot.HoleCards, got)
+	}
+
+	if _, _, err := Canonicalize(hole, Board{Cards: mustCards(t, "Ah 7c 9d")}); err == nil {
+		t.Fatalf("overlapping spot was accepted")
+	}
+}
+
+func TestCanonicalize_Counts(t *testing.T) {
+	t.Parallel()
+
+	preflop := map[CanonicalSpot]bool{}
+	eachCombination(FullCardSet.Cards(), 2, func(hole CardSet) {
+		spot, _, err := Canonicalize(hole.PersonalHand(), Board{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		preflop[spot] = true
+	})
+	if len(preflop) != NumHandClasses {
+		t.Fatalf("want %d preflop spots, but got %d", NumHandClasses, len(preflop))
+	}
+
+	flops := map[CanonicalSpot]bool{}
+	eachCombination(FullCardSet.Cards(), 3, func(board CardSet) {
+		spot, _, err := Canonicalize(PersonalHand{}, board.Board())
+		if err != nil {
+			t.Fatal(err)
+		}
+		flops[spot] = true
+	})
+	if len(flops) != 1755 {
+		t.Fatalf("want is 1755 flops, but got %d", len(flops))
+	}
+
+	hole := NewCardSet(mustCards(t, "Ah Kh")...)
+	flops = map[CanonicalSpot]bool{}
+	eachCombination(hole.Complement().Cards(), 3, func(board CardSet) {
+		spot, _, err := Canonicalize(hole.PersonalHand(), board.Board())
+		if err != nil {
+			t.Fatal(err)
+		}
+		flops[spot] = true
+	})
+	// suited hole cards fix one suit, so only the other three can be
+	// permuted
+	if len(flops) != 4494 {
+		t.Fatalf("want is 4494 flops with suited hole cards, but got %d", len(flops))
+	}
+}
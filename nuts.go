@@ -0,0 +1,118 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+)
+
+type RankedHolding struct {
+	Hand  PersonalHand
+	Best  *Hand
+	Value HandValue
+	// Tier is 1 for the nuts, 2 for the second nuts and so on. Holdings
+	// making hands of equal strength share a tier.
+	Tier int
+}
+
+// RankHoldings orders every two-card holding possible on the board from the
+// strongest hand to the weakest.
+func RankHoldings(board Board) ([]RankedHolding, error) {
+	boardSet, err := rankableBoard(board)
+	if err != nil {
+		return nil, err
+	}
+
+	var holdings []RankedHolding
+	eachCombination(boardSet.Complement().Cards(), 2, func(hole CardSet) {
+		holdings = append(holdings, RankedHolding{
+			Hand:  comboHand(hole),
+			Value: boardSet.Union(hole).Evaluate(),
+		})
+	})
+	sort.SliceStable(holdings, func(i, j int) bool {
+		if holdings[i].Value != holdings[j].Value {
+			return holdings[i].Value > holdings[j].Value
+		}
+		return comboLess(holdings[i].Hand, holdings[j].Hand)
+	})
+
+	for i := range holdings {
+		h := &holdings[i]
+		h.Tier = 1
+		if i > 0 {
+			prev := holdings[i-1]
+			h.Tier = prev.Tier
+			if h.Value != prev.Value {
+				h.Tier++
+			}
+		}
+		h.Best, err = BestHand(append(append([]Card(nil), h.Hand.Cards...), board.Cards...))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return holdings, nil
+}
+
+// NutTier returns 1 if hole is the nuts on the board, 2 if it is the second
+// nuts and so on.
+func NutTier(hole PersonalHand, board Board) (int, error) {
+	holeSet, err := rankableHolding(hole, board)
+	if err != nil {
+		return 0, err
+	}
+	boardSet := board.CardSet()
+	mine := boardSet.Union(holeSet).Evaluate()
+
+	better := map[HandValue]bool{}
+	eachCombination(boardSet.Complement().Cards(), 2, func(other CardSet) {
+		if v := boardSet.Union(other).Evaluate(); v > mine {
+			better[v] = true
+		}
+	})
+	return len(better) + 1, nil
+}
+
+// HandPercentile returns the share of the holdings an opponent can have that
+// hole beats, counting ties as half. Holdings sharing a card with hole are
+// left out.
+func HandPercentile(hole PersonalHand, board Board) (float64, error) {
+	holeSet, err := rankableHolding(hole, board)
+	if err != nil {
+		return 0, err
+	}
+	boardSet := board.CardSet()
+	mine := boardSet.Union(holeSet).Evaluate()
+
+	var score, total float64
+	eachCombination(boardSet.Union(holeSet).Complement().Cards(), 2, func(other CardSet) {
+		switch v := boardSet.Union(other).Evaluate(); {
+		case mine > v:
+			score++
+		case mine == v:
+			score += 0.5
+		}
+		total++
+	})
+	return score / total, nil
+}
+
+func rankableBoard(board Board) (CardSet, error) {
+	cs := board.CardSet()
+	if n := len(board.Cards); n < 3 || n > 5 || cs.Len() != n {
+		return 0, fmt.Errorf("poker: invalid board %v", board.Cards)
+	}
+	return cs, nil
+}
+
+func rankableHolding(hole PersonalHand, board Board) (CardSet, error) {
+	boardSet, err := rankableBoard(board)
+	if err != nil {
+		return 0, err
+	}
+	cs := hole.CardSet()
+	if len(hole.Cards) != 2 || cs.Len() != 2 || cs.Overlaps(boardSet) {
+		return 0, fmt.Errorf("poker: invalid holding %v on board %v", hole.Cards, board.Cards)
+	}
+	return cs, nil
+}
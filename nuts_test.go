@@ -0,0 +1,97 @@
+package poker
+
+import "testing"
+
+func TestRankHoldings(t *testing.T) {
+	t.Parallel()
+
+	board := Board{Cards: mustCards(t, "Kh Qh Jc 3d 8s")}
+	holdings, err := RankHoldings(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings) != 1081 {
+		t.Fatalf("want 1081 holdings, but got %d", len(holdings))
+	}
+
+	first := holdings[0]
+	if first.Tier != 1 || first.Best.Rank() != Straight || first.Best.Cards[0].Rank != Ace {
+		t.Fatalf("unexpected nuts %v: %v", first.Hand.Cards, first.Best.Cards)
+	}
+	for i := 1; i < len(holdings); i++ {
+		prev, cur := holdings[i-1], holdings[i]
+		switch cur.Best.Compare(prev.Best) {
+		case Win:
+			t.Fatalf("%v is ranked below %v", cur.Hand.Cards, prev.Hand.Cards)
+		case Draw:
+			if cur.Tier != prev.Tier {
+				t.Fatalf("%v and %v draw but have tiers %d and %d", cur.Hand.Cards, prev.Hand.Cards, cur.Tier, prev.Tier)
+			}
+		case Lose:
+			if cur.Tier != prev.Tier+1 {
+				t.Fatalf("%v has tier %d after tier %d", cur.Hand.Cards, cur.Tier, prev.Tier)
+			}
+		}
+	}
+
+	if _, err := RankHoldings(Board{Cards: mustCards(t, "Kh Qh")}); err == nil {
+		t.Fatalf("preflop board was accepted")
+	}
+}
+
+func TestNutTier(t *testing.T) {
+	t.Parallel()
+
+	board := Board{Cards: mustCards(t, "Kh Qh Jc 3d 8s")}
+	tests := []struct {
+		name    string
+		hole    string
+		want    int
+		wantErr bool
+	}{
+		{name: "nuts", hole: "As Td", want: 1},
+		{name: "second nuts", hole: "Ts 9d", want: 2},
+		{name: "third nuts", hole: "Kd Ks", want: 3},
+		{name: "blocked by board", hole: "Kh As", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NutTier(PersonalHand{Cards: mustCards(t, tt.hole)}, board)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("want %d, but got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandPercentile(t *testing.T) {
+	t.Parallel()
+
+	board := Board{Cards: mustCards(t, "As Ks Qs Js Ts")}
+	got, err := HandPercentile(PersonalHand{Cards: mustCards(t, "2c 3c")}, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 0.5 {
+		t.Fatalf("want 0.5 on a royal board, but got %v", got)
+	}
+
+	board = Board{Cards: mustCards(t, "Kh Qh Jc 3d 8s")}
+	nuts, err := HandPercentile(PersonalHand{Cards: mustCards(t, "As Td")}, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weak, err := HandPercentile(PersonalHand{Cards: mustCards(t, "4c 2c")}, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nuts <= 0.99 || weak >= 0.05 || nuts > 1 || weak < 0 {
+		t.Fatalf("unexpected percentiles %v and %v", nuts, weak)
+	}
+}
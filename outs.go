@@ -0,0 +1,151 @@
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+type DrawKind int
+
+const (
+	FlushDraw DrawKind = iota + 1
+	// OpenEndedStraightDraw also covers double gutshots, which have the same
+	// number of outs.
+	OpenEndedStraightDraw
+	GutshotStraightDraw
+	BackdoorFlushDraw
+	BackdoorStraightDraw
+)
+
+var drawToName = map[DrawKind]string{
+	FlushDraw:             "flush draw",
+	OpenEndedStraightDraw: "open-ended straight draw",
+	GutshotStraightDraw:   "gutshot straight draw",
+	BackdoorFlushDraw:     "backdoor flush draw",
+	BackdoorStraightDraw:  "backdoor straight draw",
+}
+
+func (d DrawKind) String() string {
+	return drawToName[d]
+}
+
+type Out struct {
+	Card Card
+	// Rank is the hand rank made when the card comes.
+	Rank HandRank
+}
+
+type OutsAnalysis struct {
+	Hand   *Hand
+	Outs   []Out
+	Draws  []DrawKind
+	Unseen int
+	// HitProbability is the chance of catching at least one out by the river.
+	HitProbability float64
+}
+
+func AnalyzeOuts(hole PersonalHand, board Board) (*OutsAnalysis, error) {
+	holeSet, boardSet := hole.CardSet(), board.CardSet()
+	if len(hole.Cards) != 2 || holeSet.Len() != 2 {
+		return nil, fmt.Errorf("poker: invalid holding %v", hole.Cards)
+	}
+	if n := len(board.Cards); n != 3 && n != 4 || boardSet.Len() != n {
+		return nil, fmt.Errorf("poker: board must be a flop or a turn, got %v", board.Cards)
+	}
+	if holeSet.Overlaps(boardSet) {
+		return nil, fmt.Errorf("poker: holding %v overlaps the board %v", hole.Cards, board.Cards)
+	}
+
+	known := holeSet.Union(boardSet)
+	best, err := BestHand(known.Cards())
+	if err != nil {
+		return nil, err
+	}
+	a := &OutsAnalysis{Hand: best}
+
+	unseen := FullCardSet.Difference(known)
+	a.Unseen = unseen.Len()
+	unseen.Each(func(c Card) bool {
+		if r := known.Add(c).Evaluate().Rank(); r > best.Rank() {
+			a.Outs = append(a.Outs, Out{Card: c, Rank: r})
+		}
+		return true
+	})
+	a.HitProbability = HitProbability(len(a.Outs), a.Unseen, 5-len(board.Cards))
+	a.Draws = draws(holeSet, boardSet, best.Rank())
+	return a, nil
+}
+
+// HitProbability returns the chance that at least one of outs is among the
+// next cardsToCome cards drawn from unseen cards.
+func HitProbability(outs, unseen, cardsToCome int) float64 {
+	if outs <= 0 || unseen <= 0 {
+		return 0
+	}
+	miss := 1.0
+	for k := 0; k < cardsToCome; k++ {
+		if unseen-k <= 0 {
+			break
+		}
+		miss *= float64(unseen-outs-k) / float64(unseen-k)
+		if miss <= 0 {
+			return 1
+		}
+	}
+	return 1 - miss
+}
+
+func draws(hole, board CardSet, rank HandRank) []DrawKind {
+	var ds []DrawKind
+	all := hole.Union(board)
+	flop := board.Len() == 3
+
+	if rank < Flush {
+		for s := 0; s < 4; s++ {
+			if hole.suitMask(s) == 0 {
+				continue
+			}
+			switch n := bits.OnesCount16(all.suitMask(s)); {
+			case n == 4:
+				ds = append(ds, FlushDraw)
+			case n == 3 && flop:
+				ds = append(ds, BackdoorFlushDraw)
+			}
+		}
+	}
+
+	if rank < Straight {
+		allRanks, boardRanks := all.rankMask(), board.rankMask()
+		completers := 0
+		for i := uint(0); i < 13; i++ {
+			if b := uint16(1) << i; allRanks&b == 0 && straightHigh(allRanks|b) > straightHigh(boardRanks|b) {
+				completers++
+			}
+		}
+		switch {
+		case completers >= 2:
+			ds = append(ds, OpenEndedStraightDraw)
+		case completers == 1:
+			ds = append(ds, GutshotStraightDraw)
+		case flop && backdoorStraight(allRanks, boardRanks):
+			ds = append(ds, BackdoorStraightDraw)
+		}
+	}
+	return ds
+}
+
+func backdoorStraight(allRanks, boardRanks uint16) bool {
+	for i := uint(0); i < 13; i++ {
+		for j := i + 1; j < 13; j++ {
+			b := uint16(1)<<i | uint16(1)<<j
+			if allRanks&b == 0 && straightHigh(allRanks|b) > straightHigh(boardRanks|b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cs CardSet) rankMask() uint16 {
+	return cs.suitMask(0) | cs.suitMask(1) | cs.suitMask(2) | cs.suitMask(3)
+}
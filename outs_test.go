@@ -0,0 +1,168 @@
+package poker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestAnalyzeOuts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		hole      string
+		board     string
+		wantRank  HandRank
+		wantOuts  int
+		wantDraws []DrawKind
+		wantErr   bool
+	}{
+		{
+			name:      "flush draw",
+			hole:      "Ah Kh",
+			board:     "2h 7h 9c",
+			wantRank:  HighCard,
+			wantOuts:  23,
+			wantDraws: []DrawKind{FlushDraw},
+		},
+		{
+			name:      "open-ended straight draw",
+			hole:      "9s 8d",
+			board:     "7c 6h 2s",
+			wantRank:  HighCard,
+			wantOuts:  8 + 15,
+			wantDraws: []DrawKind{OpenEndedStraightDraw},
+		},
+		{
+			name:    "duplicated board card",
+			hole:    "9s 8d",
+			board:   "6h 5c Ks Ks",
+			wantErr: true,
+		},
+		{
+			name:      "gutshot",
+			hole:      "9s 8d",
+			board:     "6h 5c Ks",
+			wantRank:  HighCard,
+			wantOuts:  4 + 15,
+			wantDraws: []DrawKind{GutshotStraightDraw},
+		},
+		{
+			name:      "backdoor draws",
+			hole:      "Tc 9c",
+			board:     "8c 2d Ks",
+			wantRank:  HighCard,
+			wantOuts:  15,
+			wantDraws: []DrawKind{BackdoorFlushDraw, BackdoorStraightDraw},
+		},
+		{
+			name:      "straight draw on the board",
+			hole:      "As Ad",
+			board:     "5c 6d 7h 8s",
+			wantRank:  OnePair,
+			wantOuts:  2 + 8 + 12,
+			wantDraws: nil,
+		},
+		{
+			name:      "made flush",
+			hole:      "Ah Kh",
+			board:     "2h 7h 9h Jh",
+			wantRank:  Flush,
+			wantOuts:  0,
+			wantDraws: nil,
+		},
+		{
+			name:    "river",
+			hole:    "Ah Kh",
+			board:   "2h 7h 9h Jh Qh",
+			wantErr: true,
+		},
+		{
+			name:    "overlapping cards",
+			hole:    "Ah Kh",
+			board:   "Ah 7h 9h",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, err := AnalyzeOuts(PersonalHand{Cards: mustCards(t, tt.hole)}, Board{Cards: mustCards(t, tt.board)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if a.Hand.Rank() != tt.wantRank {
+				t.Fatalf("want is %s, but got %s", tt.wantRank, a.Hand.Rank())
+			}
+			if len(a.Outs) != tt.wantOuts {
+				t.Fatalf("want %d outs, but got %d: %v", tt.wantOuts, len(a.Outs), a.Outs)
+			}
+			for _, o := range a.Outs {
+				if o.Rank <= tt.wantRank {
+					t.Fatalf("out %v does not improve the hand", o)
+				}
+			}
+			if diff := cmp.Diff(a.Draws, tt.wantDraws, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("want and got are different(-got +want): %s", diff)
+			}
+			want := HitProbability(tt.wantOuts, a.Unseen, 5-len(mustCards(t, tt.board)))
+			if a.HitProbability != want {
+				t.Fatalf("want hit probability %v, but got %v", want, a.HitProbability)
+			}
+		})
+	}
+}
+
+func TestAnalyzeOuts_FlushOuts(t *testing.T) {
+	t.Parallel()
+
+	a, err := AnalyzeOuts(PersonalHand{Cards: mustCards(t, "Ah Kh")}, Board{Cards: mustCards(t, "2h 7h 9c")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flushes := 0
+	for _, o := range a.Outs {
+		if o.Rank == Flush {
+			flushes++
+			if o.Card.Suit != Heart {
+				t.Fatalf("%v makes a flush", o.Card)
+			}
+		}
+	}
+	if flushes != 9 {
+		t.Fatalf("want 9 flush outs, but got %d", flushes)
+	}
+}
+
+func TestHitProbability(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                      string
+		outs, unseen, cardsToCome int
+		want                      float64
+	}{
+		{name: "flush draw on the flop", outs: 9, unseen: 47, cardsToCome: 2, want: 1 - 38.0/47*37.0/46},
+		{name: "flush draw on the turn", outs: 9, unseen: 46, cardsToCome: 1, want: 9.0 / 46},
+		{name: "no outs", outs: 0, unseen: 46, cardsToCome: 1, want: 0},
+		{name: "every card", outs: 46, unseen: 46, cardsToCome: 1, want: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := HitProbability(tt.outs, tt.unseen, tt.cardsToCome); math.Abs(got-tt.want) > 1e-12 {
+				t.Fatalf("want %v, but got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,416 @@
+// Package pushfold solves push or fold preflop games for short stacks.
+package pushfold
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/icm"
+)
+
+const maxPlayers = 6
+
+type Config struct {
+	// Stacks holds the chips of each player in the order they act
+	// preflop. The last two players post the small and big blinds; with
+	// two players the first one is the small blind.
+	Stacks     []float64
+	SmallBlind float64
+	BigBlind   float64
+	Ante       float64
+	// Payouts switches from chip EV to ICM equity when set.
+	Payouts []float64
+	// Samples is the number of deals sampled for each hand class of each
+	// player. It defaults to 20000 heads up and 2000 otherwise.
+	Samples int
+	// Iterations is the number of fictitious play iterations. It defaults
+	// to 300.
+	Iterations int
+	Seed       int64
+}
+
+// Decision is the strategy of Player when Pushers have moved all in before
+// it and everyone else has folded. An empty Pushers is an open shove.
+type Decision struct {
+	Player  int
+	Pushers []int
+	// Range holds the hands that go all in, weighted by how often they do.
+	Range *poker.Range
+}
+
+type Result struct {
+	Decisions []Decision
+}
+
+func (r *Result) Decision(player int, pushers ...int) (Decision, bool) {
+	var set uint
+	for _, p := range pushers {
+		set |= 1 << uint(p)
+	}
+	for _, d := range r.Decisions {
+		var ds uint
+		for _, p := range d.Pushers {
+			ds |= 1 << uint(p)
+		}
+		if d.Player == player && ds == set {
+			return d, true
+		}
+	}
+	return Decision{}, false
+}
+
+// group aggregates the sampled deals in which every player holds the same
+// hand classes.
+type group struct {
+	classes [maxPlayers]uint8
+	weight  float64
+	// payoffs holds the average payoff of the sampled player for every set
+	// of players going all in.
+	payoffs []float64
+}
+
+type node struct {
+	player  int
+	pushers uint
+}
+
+type solver struct {
+	cfg Config
+	n   int
+	// nodes maps a player and the players all in before it to the index of
+	// its strategy, or -1 where the player has no decision.
+	nodes    [maxPlayers][1 << maxPlayers]int
+	strategy [][poker.NumHandClasses]float64
+	groups   [maxPlayers][poker.NumHandClasses][]group
+	icmCache map[[maxPlayers]float64][]float64
+}
+
+func Solve(cfg Config) (*Result, error) {
+	n := len(cfg.Stacks)
+	if n < 2 || n > maxPlayers {
+		return nil, fmt.Errorf("pushfold: need 2 to %d players, got %d", maxPlayers, n)
+	}
+	if cfg.SmallBlind < 0 || cfg.BigBlind <= 0 || cfg.Ante < 0 {
+		return nil, errors.New("pushfold: invalid blinds")
+	}
+	for _, s := range cfg.Stacks {
+		if s <= 0 || math.IsNaN(s) || math.IsInf(s, 0) {
+			return nil, fmt.Errorf("pushfold: invalid stack %v", s)
+		}
+	}
+	if cfg.Samples <= 0 {
+		cfg.Samples = 2000
+		if n == 2 {
+			cfg.Samples = 20000
+		}
+	}
+	if cfg.Iterations <= 0 {
+		cfg.Iterations = 300
+	}
+
+	s := &solver{cfg: cfg, n: n, icmCache: map[[maxPlayers]float64][]float64{}}
+	for p := 0; p < n; p++ {
+		for pushers := range s.nodes[p] {
+			s.nodes[p][pushers] = -1
+			if pushers >= 1<<uint(p) || p == n-1 && pushers == 0 {
+				// everyone folded to the big blind
+				continue
+			}
+			s.nodes[p][pushers] = len(s.strategy)
+			s.strategy = append(s.strategy, [poker.NumHandClasses]float64{})
+		}
+	}
+	if err := s.sample(); err != nil {
+		return nil, err
+	}
+	s.iterate()
+	return s.result(), nil
+}
+
+func (s *solver) blind(player int) float64 {
+	switch {
+	case player == s.n-1:
+		return s.cfg.BigBlind
+	case player == s.n-2:
+		return s.cfg.SmallBlind
+	default:
+		return 0
+	}
+}
+
+// posted returns what player puts in before acting, capped by its stack.
+func (s *solver) posted(player int) float64 {
+	return math.Min(s.cfg.Stacks[player], s.cfg.Ante+s.blind(player))
+}
+
+func (s *solver) sample() error {
+	rnd := rand.New(rand.NewSource(s.cfg.Seed))
+	classOf := map[poker.CardSet]uint8{}
+	var all []poker.CardSet
+	for _, c := range poker.HandClasses() {
+		for _, h := range c.Combos() {
+			classOf[h.CardSet()] = uint8(c.Index())
+			all = append(all, h.CardSet())
+		}
+	}
+
+	// Every other player walks through its own shuffled list of holdings,
+	// so the holdings it gets are spread evenly instead of at random.
+	var queues [maxPlayers][]poker.CardSet
+	var next [maxPlayers]int
+	for i := 0; i < s.n; i++ {
+		queues[i] = append([]poker.CardSet(nil), all...)
+		rnd.Shuffle(len(queues[i]), func(a, b int) { queues[i][a], queues[i][b] = queues[i][b], queues[i][a] })
+	}
+	draw := func(i int, used poker.CardSet) poker.CardSet {
+		for {
+			h := queues[i][next[i]%len(queues[i])]
+			next[i]++
+			if !h.Overlaps(used) {
+				return h
+			}
+		}
+	}
+
+	deck := poker.FullCardSet.Cards()
+	holes := make([]poker.CardSet, s.n)
+	values := make([]poker.HandValue, s.n)
+	for p := 0; p < s.n; p++ {
+		for _, class := range poker.HandClasses() {
+			combos := class.Combos()
+			byClasses := map[[maxPlayers]uint8]int{}
+			var groups []group
+			for k := 0; k < s.cfg.Samples; k++ {
+				holes[p] = combos[k%len(combos)].CardSet()
+				used := holes[p]
+				for i := range holes {
+					if i != p {
+						holes[i] = draw(i, used)
+						used = used.Union(holes[i])
+					}
+				}
+				board := poker.CardSet(0)
+				for board.Len() < 5 {
+					c := deck[rnd.Intn(len(deck))]
+					if !used.Contains(c) {
+						board = board.Add(c)
+						used = used.Add(c)
+					}
+				}
+
+				var classes [maxPlayers]uint8
+				for i, h := range holes {
+					classes[i] = classOf[h]
+					values[i] = h.Union(board).Evaluate()
+				}
+				gi, ok := byClasses[classes]
+				if !ok {
+					gi = len(groups)
+					byClasses[classes] = gi
+					groups = append(groups, group{classes: classes, payoffs: make([]float64, 1<<uint(s.n))})
+				}
+				g := &groups[gi]
+				g.weight++
+				for pushers := range g.payoffs {
+					payoff, err := s.payoff(p, uint(pushers), values)
+					if err != nil {
+						return err
+					}
+					g.payoffs[pushers] += (payoff - g.payoffs[pushers]) / g.weight
+				}
+			}
+			s.groups[p][class.Index()] = groups
+		}
+	}
+	return nil
+}
+
+// payoff settles the hand in which pushers went all in and everyone else
+// folded, and returns what player ends up with compared to its stack.
+func (s *solver) payoff(player int, pushers uint, values []poker.HandValue) (float64, error) {
+	contrib := make([]float64, s.n)
+	final := make([]float64, s.n)
+	for i := range contrib {
+		if pushers&(1<<uint(i)) != 0 {
+			contrib[i] = s.cfg.Stacks[i]
+		} else {
+			contrib[i] = s.posted(i)
+		}
+		final[i] = s.cfg.Stacks[i] - contrib[i]
+	}
+
+	if pushers == 0 {
+		// the big blind wins the blinds and antes uncontested
+		pushers = 1 << uint(s.n-1)
+	}
+
+	// split the pot into layers by the contributions of the players still
+	// in, so everyone only competes for what it matched
+	settled := 0.0
+	for {
+		level := math.Inf(1)
+		for i := range contrib {
+			if pushers&(1<<uint(i)) != 0 && contrib[i] > settled && contrib[i] < level {
+				level = contrib[i]
+			}
+		}
+		if math.IsInf(level, 1) {
+			break
+		}
+		pot := 0.0
+		for i := range contrib {
+			pot += math.Max(0, math.Min(contrib[i], level)-settled)
+		}
+		var best poker.HandValue
+		var winners []int
+		for i := range contrib {
+			if pushers&(1<<uint(i)) == 0 || contrib[i] < level {
+				continue
+			}
+			switch {
+			case len(winners) == 0 || values[i] > best:
+				best, winners = values[i], []int{i}
+			case values[i] == best:
+				winners = append(winners, i)
+			}
+		}
+		for _, w := range winners {
+			final[w] += pot / float64(len(winners))
+		}
+		settled = level
+	}
+	// blinds above what any player still in matched go back to their owners
+	for i := range contrib {
+		final[i] += math.Max(0, contrib[i]-settled)
+	}
+
+	if len(s.cfg.Payouts) == 0 {
+		return final[player] - s.cfg.Stacks[player], nil
+	}
+	var key [maxPlayers]float64
+	copy(key[:], final)
+	equity, ok := s.icmCache[key]
+	if !ok {
+		var err error
+		if equity, err = icm.Equity(final, s.cfg.Payouts); err != nil {
+			return 0, err
+		}
+		s.icmCache[key] = equity
+	}
+	return equity[player], nil
+}
+
+// continuation returns the expected payoff of the sampled player once the
+// players before next have acted and pushers went all in.
+func (s *solver) continuation(g *group, next int, pushers uint) float64 {
+	if next == s.n || next == s.n-1 && pushers == 0 {
+		return g.payoffs[pushers]
+	}
+	q := s.strategy[s.nodes[next][pushers]][g.classes[next]]
+	v := 0.0
+	if q < 1 {
+		v += (1 - q) * s.continuation(g, next+1, pushers)
+	}
+	if q > 0 {
+		v += q * s.continuation(g, next+1, pushers|1<<uint(next))
+	}
+	return v
+}
+
+// reach returns how likely the players before player take the actions
+// leading to pushers.
+func (s *solver) reach(g *group, player int, pushers uint) float64 {
+	w := g.weight
+	for j := 0; j < player; j++ {
+		q := s.strategy[s.nodes[j][pushers&(1<<uint(j)-1)]][g.classes[j]]
+		if pushers&(1<<uint(j)) != 0 {
+			w *= q
+		} else {
+			w *= 1 - q
+		}
+		if w == 0 {
+			return 0
+		}
+	}
+	return w
+}
+
+func (s *solver) bestResponse(nd node, class int) float64 {
+	var push, fold, total float64
+	groups := s.groups[nd.player][class]
+	for k := range groups {
+		g := &groups[k]
+		w := s.reach(g, nd.player, nd.pushers)
+		if w == 0 {
+			continue
+		}
+		fold += w * s.continuation(g, nd.player+1, nd.pushers)
+		push += w * s.continuation(g, nd.player+1, nd.pushers|1<<uint(nd.player))
+		total += w
+	}
+	if total == 0 {
+		// the node is never reached holding this class
+		return s.strategy[s.nodes[nd.player][nd.pushers]][class]
+	}
+	if push > fold {
+		return 1
+	}
+	return 0
+}
+
+// iterate runs fictitious play: every player best responds to the average
+// strategies of the others, and the averages move towards the responses.
+func (s *solver) iterate() {
+	for i := range s.strategy {
+		for class := range s.strategy[i] {
+			s.strategy[i][class] = 0.5
+		}
+	}
+	for t := 1; t <= s.cfg.Iterations; t++ {
+		responses := make([][poker.NumHandClasses]float64, len(s.strategy))
+		for p := 0; p < s.n; p++ {
+			for pushers, i := range s.nodes[p] {
+				if i < 0 {
+					continue
+				}
+				for class := 0; class < poker.NumHandClasses; class++ {
+					responses[i][class] = s.bestResponse(node{player: p, pushers: uint(pushers)}, class)
+				}
+			}
+		}
+		for i := range s.strategy {
+			for class := range s.strategy[i] {
+				s.strategy[i][class] += (responses[i][class] - s.strategy[i][class]) / float64(t+1)
+			}
+		}
+	}
+}
+
+func (s *solver) result() *Result {
+	classes := poker.HandClasses()
+	r := &Result{}
+	for p := 0; p < s.n; p++ {
+		for pushers, i := range s.nodes[p] {
+			if i < 0 {
+				continue
+			}
+			d := Decision{Player: p, Range: poker.NewRange()}
+			for j := 0; j < p; j++ {
+				if pushers&(1<<j) != 0 {
+					d.Pushers = append(d.Pushers, j)
+				}
+			}
+			// rounded to 5%, below which fictitious play is still noisy
+			for class, q := range s.strategy[i] {
+				if w := math.Round(q*20) / 20; w > 0 {
+					_ = d.Range.AddClass(classes[class], w)
+				}
+			}
+			r.Decisions = append(r.Decisions, d)
+		}
+	}
+	return r
+}
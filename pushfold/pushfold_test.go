@@ -0,0 +1,136 @@
+package pushfold
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker"
+)
+
+func share(r *poker.Range) float64 {
+	total := 0.0
+	for _, c := range r.Combos() {
+		total += c.Weight
+	}
+	return total / 1326
+}
+
+func TestSolve(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  Config
+		// wantShares holds the bounds of the share of hands pushed by each
+		// decision, in the order of the decisions
+		wantShares [][2]float64
+		wantPush   []string
+		wantFold   []string
+	}{
+		{
+			name:       "heads up 10bb",
+			cfg:        Config{Stacks: []float64{10, 10}, SmallBlind: 0.5, BigBlind: 1, Samples: 3000, Iterations: 100},
+			wantShares: [][2]float64{{0.5, 0.7}, {0.3, 0.45}},
+			wantPush:   []string{"AA", "A2o", "KQo"},
+			wantFold:   []string{"72o", "32o"},
+		},
+		{
+			name:       "heads up 3bb",
+			cfg:        Config{Stacks: []float64{3, 3}, SmallBlind: 0.5, BigBlind: 1, Samples: 3000, Iterations: 100},
+			wantShares: [][2]float64{{0.7, 1}, {0.6, 1}},
+			wantPush:   []string{"AA", "K2o"},
+		},
+		{
+			name:       "three handed icm",
+			cfg:        Config{Stacks: []float64{10, 10, 10}, SmallBlind: 0.5, BigBlind: 1, Payouts: []float64{0.5, 0.3, 0.2}, Samples: 300, Iterations: 50},
+			wantShares: [][2]float64{{0.1, 0.4}, {0.4, 0.9}, {0, 0.2}, {0, 0.2}, {0.1, 0.45}, {0, 0.15}},
+			wantPush:   []string{"AA", "KK"},
+			wantFold:   []string{"72o"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Solve(tt.cfg)
+			if err != nil {
+				t.Fatalf("Solve() error: %v", err)
+			}
+			if len(got.Decisions) != len(tt.wantShares) {
+				t.Fatalf("want is %d decisions, but got %d", len(tt.wantShares), len(got.Decisions))
+			}
+			for i, d := range got.Decisions {
+				if s := share(d.Range); s < tt.wantShares[i][0] || s > tt.wantShares[i][1] {
+					t.Errorf("player %d facing %v: want is a share in %v, but got %.3f: %s", d.Player, d.Pushers, tt.wantShares[i], s, d.Range)
+				}
+				for _, s := range tt.wantPush {
+					c, _ := poker.ParseHandClass(s)
+					if w := d.Range.Weight(c.Combos()[0]); w != 1 {
+						t.Errorf("player %d facing %v: want is %s pushed with weight 1, but got %v", d.Player, d.Pushers, s, w)
+					}
+				}
+				if len(d.Pushers) == 0 {
+					continue
+				}
+				for _, s := range tt.wantFold {
+					c, _ := poker.ParseHandClass(s)
+					if w := d.Range.Weight(c.Combos()[0]); w != 0 {
+						t.Errorf("player %d facing %v: want is %s pushed with weight 0, but got %v", d.Player, d.Pushers, s, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSolve_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "one player", cfg: Config{Stacks: []float64{10}, SmallBlind: 0.5, BigBlind: 1}},
+		{name: "too many players", cfg: Config{Stacks: []float64{1, 1, 1, 1, 1, 1, 1}, SmallBlind: 0.5, BigBlind: 1}},
+		{name: "empty stack", cfg: Config{Stacks: []float64{10, 0}, SmallBlind: 0.5, BigBlind: 1}},
+		{name: "no big blind", cfg: Config{Stacks: []float64{10, 10}, SmallBlind: 0.5}},
+		{name: "negative ante", cfg: Config{Stacks: []float64{10, 10}, SmallBlind: 0.5, BigBlind: 1, Ante: -1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Solve(tt.cfg); err == nil {
+				t.Error("want error but got nil")
+			}
+		})
+	}
+}
+
+func TestResult_Decision(t *testing.T) {
+	t.Parallel()
+	r := &Result{Decisions: []Decision{
+		{Player: 0},
+		{Player: 1},
+		{Player: 1, Pushers: []int{0}},
+		{Player: 2, Pushers: []int{0, 1}},
+	}}
+	tests := []struct {
+		player  int
+		pushers []int
+		want    Decision
+		wantOK  bool
+	}{
+		{player: 1, want: Decision{Player: 1}, wantOK: true},
+		{player: 1, pushers: []int{0}, want: Decision{Player: 1, Pushers: []int{0}}, wantOK: true},
+		{player: 2, pushers: []int{1, 0}, want: Decision{Player: 2, Pushers: []int{0, 1}}, wantOK: true},
+		{player: 2, pushers: []int{1}},
+	}
+	for _, tt := range tests {
+		got, ok := r.Decision(tt.player, tt.pushers...)
+		if ok != tt.wantOK {
+			t.Errorf("player %d facing %v: want is %v, but got %v", tt.player, tt.pushers, tt.wantOK, ok)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("want and got are different(-got +want): %s", diff)
+		}
+	}
+}
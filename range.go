@@ -0,0 +1,421 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Combo struct {
+	Hand   PersonalHand
+	Weight float64
+}
+
+// Range is a weighted set of two-card holdings.
+type Range struct {
+	weights map[CardSet]float64
+}
+
+func NewRange() *Range {
+	return &Range{weights: map[CardSet]float64{}}
+}
+
+func ParseRange(s string) (*Range, error) {
+	r := NewRange()
+	for _, token := range strings.Split(s, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if err := r.addToken(token); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Range) addToken(token string) error {
+	body, weight := token, 1.0
+	if i := strings.IndexByte(token, ':'); i >= 0 {
+		body = strings.TrimSpace(token[:i])
+		w, err := strconv.ParseFloat(strings.TrimSpace(token[i+1:]), 64)
+		if err != nil || w < 0 || w > 1 {
+			return fmt.Errorf("poker: invalid weight in %q", token)
+		}
+		weight = w
+	}
+
+	if compact := strings.Join(strings.Fields(body), ""); len(compact) == 4 {
+		body = compact
+	}
+	if len(body) == 4 && Suit(strings.ToLower(body[1:2])).index() >= 0 && Suit(strings.ToLower(body[3:4])).index() >= 0 {
+		cards, err := ParseCards(body)
+		if err != nil {
+			return err
+		}
+		if cards[0] == cards[1] {
+			return fmt.Errorf("poker: duplicated card in %q", token)
+		}
+		r.set(NewCardSet(cards...), weight)
+		return nil
+	}
+
+	classes, err := parseClassExpr(body)
+	if err != nil {
+		return fmt.Errorf("poker: invalid range token %q: %w", token, err)
+	}
+	for _, p := range classes {
+		for _, c := range p.classes() {
+			for _, cs := range c.combos() {
+				r.set(cs, weight)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Range) set(cs CardSet, weight float64) {
+	if weight == 0 {
+		delete(r.weights, cs)
+		return
+	}
+	r.weights[cs] = weight
+}
+
+func (r *Range) Add(h PersonalHand, weight float64) error {
+	cs := h.CardSet()
+	if len(h.Cards) != 2 || cs.Len() != 2 {
+		return fmt.Errorf("poker: invalid holding %v", h.Cards)
+	}
+	r.set(cs, weight)
+	return nil
+}
+
+func (r *Range) Weight(h PersonalHand) float64 {
+	return r.weights[h.CardSet()]
+}
+
+func (r *Range) Len() int {
+	return len(r.weights)
+}
+
+func (r *Range) Combos() []Combo {
+	keys := r.sortedKeys()
+	combos := make([]Combo, len(keys))
+	for i, cs := range keys {
+		combos[i] = Combo{Hand: comboHand(cs), Weight: r.weights[cs]}
+	}
+	return combos
+}
+
+// Exclude returns a copy of the range without the combos blocked by dead.
+func (r *Range) Exclude(dead CardSet) *Range {
+	excluded := NewRange()
+	for cs, w := range r.weights {
+		if !cs.Overlaps(dead) {
+			excluded.weights[cs] = w
+		}
+	}
+	return excluded
+}
+
+func (r *Range) sortedKeys() []CardSet {
+	keys := make([]CardSet, 0, len(r.weights))
+	for cs := range r.weights {
+		keys = append(keys, cs)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return comboLess(comboHand(keys[i]), comboHand(keys[j]))
+	})
+	return keys
+}
+
+// comboHand returns the two cards of cs with the higher ranked card first.
+func comboHand(cs CardSet) PersonalHand {
+	cards := cs.Cards()
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].Rank != cards[j].Rank {
+			return cards[i].Rank > cards[j].Rank
+		}
+		return cards[i].Suit.index() < cards[j].Suit.index()
+	})
+	return PersonalHand{Cards: cards}
+}
+
+func comboLess(a, b PersonalHand) bool {
+	for i := range a.Cards {
+		if a.Cards[i].Rank != b.Cards[i].Rank {
+			return a.Cards[i].Rank > b.Cards[i].Rank
+		}
+	}
+	for i := range a.Cards {
+		if a.Cards[i].Suit != b.Cards[i].Suit {
+			return a.Cards[i].Suit.index() < b.Cards[i].Suit.index()
+		}
+	}
+	return false
+}
+
+type suitedness int
+
+const (
+	anySuitedness suitedness = iota
+	suited
+	offsuit
+)
+
+type classPattern struct {
+	high, low CardRank
+	suit      suitedness
+}
+
+// classes expands the pattern into hand classes, splitting a non-pair
+// without suitedness into its suited and offsuit classes.
+func (c classPattern) classes() []HandClass {
+	switch {
+	case c.high == c.low:
+		return []HandClass{{High: c.high, Low: c.low}}
+	case c.suit == anySuitedness:
+		return []HandClass{{High: c.high, Low: c.low, Suited: true}, {High: c.high, Low: c.low}}
+	default:
+		return []HandClass{{High: c.high, Low: c.low, Suited: c.suit == suited}}
+	}
+}
+
+func parseClass(s string) (classPattern, error) {
+	if len(s) != 2 && len(s) != 3 {
+		return classPattern{}, fmt.Errorf("malformed hand %q", s)
+	}
+	var ranks [2]CardRank
+	for i := 0; i < 2; i++ {
+		r := strings.IndexByte(rankChars, strings.ToUpper(s[i : i+1])[0])
+		if r < 0 {
+			return classPattern{}, fmt.Errorf("invalid rank in %q", s)
+		}
+		ranks[i] = Deuce + CardRank(r)
+	}
+	c := classPattern{high: ranks[0], low: ranks[1]}
+	if c.high < c.low {
+		c.high, c.low = c.low, c.high
+	}
+	if len(s) == 3 {
+		switch strings.ToLower(s[2:]) {
+		case "s":
+			c.suit = suited
+		case "o":
+			c.suit = offsuit
+		default:
+			return classPattern{}, fmt.Errorf("invalid suitedness in %q", s)
+		}
+		if c.high == c.low {
+			return classPattern{}, fmt.Errorf("pair cannot be suited or offsuit: %q", s)
+		}
+	}
+	return c, nil
+}
+
+func parseClassExpr(s string) ([]classPattern, error) {
+	if strings.HasSuffix(s, "+") {
+		c, err := parseClass(strings.TrimSuffix(s, "+"))
+		if err != nil {
+			return nil, err
+		}
+		var classes []classPattern
+		if c.high == c.low {
+			for r := c.high; r <= Ace; r++ {
+				classes = append(classes, classPattern{high: r, low: r})
+			}
+			return classes, nil
+		}
+		for r := c.low; r < c.high; r++ {
+			classes = append(classes, classPattern{high: c.high, low: r, suit: c.suit})
+		}
+		return classes, nil
+	}
+
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		from, err := parseClass(s[:i])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseClass(s[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		if from.suit != to.suit || (from.high == from.low) != (to.high == to.low) {
+			return nil, fmt.Errorf("mismatched ends in %q", s)
+		}
+		var classes []classPattern
+		if from.high == from.low {
+			lo, hi := from.high, to.high
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			for r := lo; r <= hi; r++ {
+				classes = append(classes, classPattern{high: r, low: r})
+			}
+			return classes, nil
+		}
+		if from.high != to.high {
+			return nil, fmt.Errorf("dash range must keep the same high card in %q", s)
+		}
+		lo, hi := from.low, to.low
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		for r := lo; r <= hi; r++ {
+			classes = append(classes, classPattern{high: from.high, low: r, suit: from.suit})
+		}
+		return classes, nil
+	}
+
+	c, err := parseClass(s)
+	if err != nil {
+		return nil, err
+	}
+	return []classPattern{c}, nil
+}
+
+// classWeight returns the weight shared by every combo of c, or 0 if the
+// combos are not all present with the same weight.
+func (r *Range) classWeight(c HandClass) float64 {
+	var w float64
+	for i, cs := range c.combos() {
+		cw, ok := r.weights[cs]
+		if !ok || i > 0 && cw != w {
+			return 0
+		}
+		w = cw
+	}
+	return w
+}
+
+func formatWeight(token string, w float64) string {
+	if w == 1 {
+		return token
+	}
+	return token + ":" + strconv.FormatFloat(w, 'g', -1, 64)
+}
+
+func rankChar(r CardRank) string {
+	return rankChars[r-Deuce : r-Deuce+1]
+}
+
+type rankRun struct {
+	hi, lo CardRank
+	w      float64
+}
+
+// runs groups consecutive descending ranks holding the same weight.
+func runs(weights map[CardRank]float64, top, bottom CardRank) []rankRun {
+	var rs []rankRun
+	for rank := top; rank >= bottom; {
+		w := weights[rank]
+		if w == 0 {
+			rank--
+			continue
+		}
+		end := rank
+		for end-1 >= bottom && weights[end-1] == w {
+			end--
+		}
+		rs = append(rs, rankRun{hi: rank, lo: end, w: w})
+		rank = end - 1
+	}
+	return rs
+}
+
+// String prints the range in compact canonical notation such as
+// "QQ+, AKs, A5s-A2s, AhKd:0.5".
+func (r *Range) String() string {
+	var tokens []string
+	covered := map[CardSet]bool{}
+	cover := func(c HandClass) {
+		for _, cs := range c.combos() {
+			covered[cs] = true
+		}
+	}
+
+	pairs := map[CardRank]float64{}
+	for rank := Ace; rank >= Deuce; rank-- {
+		c := HandClass{High: rank, Low: rank}
+		if w := r.classWeight(c); w > 0 {
+			pairs[rank] = w
+			cover(c)
+		}
+	}
+	for _, x := range runs(pairs, Ace, Deuce) {
+		p, q := rankChar(x.hi)+rankChar(x.hi), rankChar(x.lo)+rankChar(x.lo)
+		switch {
+		case x.hi == Ace && x.lo != Ace:
+			tokens = append(tokens, formatWeight(q+"+", x.w))
+		case x.hi == x.lo:
+			tokens = append(tokens, formatWeight(p, x.w))
+		default:
+			tokens = append(tokens, formatWeight(p+"-"+q, x.w))
+		}
+	}
+
+	for high := Ace; high > Deuce; high-- {
+		byKind := map[bool][]rankRun{}
+		for _, s := range []bool{true, false} {
+			weights := map[CardRank]float64{}
+			for low := high - 1; low >= Deuce; low-- {
+				c := HandClass{High: high, Low: low, Suited: s}
+				if w := r.classWeight(c); w > 0 {
+					weights[low] = w
+					cover(c)
+				}
+			}
+			byKind[s] = runs(weights, high-1, Deuce)
+		}
+
+		// a suited run whose offsuit twin spans the same ranks is written
+		// once without a suffix
+		matched := map[rankRun]bool{}
+		for _, o := range byKind[false] {
+			matched[o] = true
+		}
+		var merged, suitedOnly, offsuitOnly []rankRun
+		for _, s := range byKind[true] {
+			if matched[s] {
+				merged = append(merged, s)
+				delete(matched, s)
+				continue
+			}
+			suitedOnly = append(suitedOnly, s)
+		}
+		for _, o := range byKind[false] {
+			if matched[o] {
+				offsuitOnly = append(offsuitOnly, o)
+			}
+		}
+
+		for _, group := range []struct {
+			runs   []rankRun
+			suffix string
+		}{{merged, ""}, {suitedOnly, "s"}, {offsuitOnly, "o"}} {
+			h := rankChar(high)
+			for _, x := range group.runs {
+				switch {
+				case x.hi == high-1 && x.lo != x.hi:
+					tokens = append(tokens, formatWeight(h+rankChar(x.lo)+group.suffix+"+", x.w))
+				case x.hi == x.lo:
+					tokens = append(tokens, formatWeight(h+rankChar(x.hi)+group.suffix, x.w))
+				default:
+					tokens = append(tokens, formatWeight(h+rankChar(x.hi)+group.suffix+"-"+h+rankChar(x.lo)+group.suffix, x.w))
+				}
+			}
+		}
+	}
+
+	for _, cs := range r.sortedKeys() {
+		if covered[cs] {
+			continue
+		}
+		s, _ := formatCards(comboHand(cs).Cards)
+		tokens = append(tokens, formatWeight(strings.ReplaceAll(s, " ", ""), r.weights[cs]))
+	}
+	return strings.Join(tokens, ", ")
+}
@@ -0,0 +1,127 @@
+package poker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		s         string
+		wantLen   int
+		wantPrint string
+		wantErr   bool
+	}{
+		{name: "pair", s: "QQ", wantLen: 6, wantPrint: "QQ"},
+		{name: "pair plus", s: "QQ+", wantLen: 18, wantPrint: "QQ+"},
+		{name: "pair dash", s: "88-TT", wantLen: 18, wantPrint: "TT-88"},
+		{name: "suited", s: "AKs", wantLen: 4, wantPrint: "AKs"},
+		{name: "offsuit", s: "AKo", wantLen: 12, wantPrint: "AKo"},
+		{name: "any suit", s: "AK", wantLen: 16, wantPrint: "AK"},
+		{name: "suited plus", s: "ATs+", wantLen: 16, wantPrint: "ATs+"},
+		{name: "suited dash", s: "A5s-A2s", wantLen: 16, wantPrint: "A5s-A2s"},
+		{name: "reversed rank order", s: "KAo", wantLen: 12, wantPrint: "AKo"},
+		{name: "specific combo", s: "AhKh", wantLen: 1, wantPrint: "AhKh"},
+		{name: "specific combos", s: "KdAh, Ac Kd", wantLen: 2, wantPrint: "AcKd, AhKd"},
+		{name: "weight", s: "AKs:0.5", wantLen: 4, wantPrint: "AKs:0.5"},
+		{name: "weight with spaces", s: "AKs: 0.5, KQs :0.25", wantLen: 8, wantPrint: "AKs:0.5, KQs:0.25"},
+		{name: "mixed", s: "QQ+, AKs, A5s-A2s", wantLen: 38, wantPrint: "QQ+, AKs, A5s-A2s"},
+		{name: "merge suited and offsuit", s: "KQs, KQo", wantLen: 16, wantPrint: "KQ"},
+		{name: "later token overrides", s: "QQ+, KK:0.25", wantLen: 18, wantPrint: "AA, KK:0.25, QQ"},
+		{name: "partial class", s: "AsAh, AsAd", wantLen: 2, wantPrint: "AsAd, AsAh"},
+		{name: "empty", s: "", wantLen: 0, wantPrint: ""},
+		{name: "invalid rank", s: "AZs", wantErr: true},
+		{name: "suited pair", s: "AAs", wantErr: true},
+		{name: "invalid weight", s: "AKs:2", wantErr: true},
+		{name: "dash with different high cards", s: "A5s-K2s", wantErr: true},
+		{name: "duplicated card", s: "AsAs", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := ParseRange(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if r.Len() != tt.wantLen {
+				t.Fatalf("want %d combos, but got %d", tt.wantLen, r.Len())
+			}
+			if tt.wantPrint != "" && r.String() != tt.wantPrint {
+				t.Fatalf("want %q, but got %q", tt.wantPrint, r.String())
+			}
+		})
+	}
+}
+
+func TestRange_StringRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRange("22+, A2s+, K9s+, QTs+, JTs, ATo+, KJo+, 76s:0.5, 65s:0.5, AhKd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "22+, A2s+, ATo+, K9s+, KJo+, QTs+, JTs, 76s:0.5, 65s:0.5"
+	if got := r.String(); got != want {
+		t.Fatalf("want %q, but got %q", want, got)
+	}
+
+	parsed, err := ParseRange(r.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(parsed.Combos(), r.Combos()); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+}
+
+func TestRange_Combos(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRange("AKs:0.5, 22")
+	if err != nil {
+		t.Fatal(err)
+	}
+	combos := r.Combos()
+	if len(combos) != 10 {
+		t.Fatalf("want 10 combos, but got %d", len(combos))
+	}
+	want := Combo{
+		Hand:   PersonalHand{Cards: []Card{{Suit: Spade, Rank: Ace}, {Suit: Spade, Rank: King}}},
+		Weight: 0.5,
+	}
+	if diff := cmp.Diff(combos[0], want); diff != "" {
+		t.Fatalf("want and got are different(-got +want): %s", diff)
+	}
+	if got := r.Weight(PersonalHand{Cards: []Card{{Suit: Heart, Rank: Deuce}, {Suit: Club, Rank: Deuce}}}); got != 1 {
+		t.Fatalf("want weight 1, but got %v", got)
+	}
+}
+
+func TestRange_Exclude(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRange("AA, AKs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := NewCardSet(Card{Suit: Spade, Rank: Ace}, Card{Suit: Heart, Rank: King})
+	got := r.Exclude(dead)
+	if got.Len() != 5 {
+		t.Fatalf("want 5 combos, but got %d", got.Len())
+	}
+	if want := "AcAd, AcAh, AdAh, AcKc, AdKd"; got.String() != want {
+		t.Fatalf("want %q, but got %q", want, got.String())
+	}
+	if r.Len() != 10 {
+		t.Fatalf("original range was modified")
+	}
+}
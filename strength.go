@@ -0,0 +1,180 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+)
+
+type HandStrength struct {
+	// HS is the share of opponent holdings beaten on the current board,
+	// counting ties as half.
+	HS float64
+	// PPot is the chance of ending ahead on the river when behind or tied now.
+	PPot float64
+	// NPot is the chance of ending behind on the river when ahead or tied now.
+	NPot float64
+	// EHS is the effective hand strength HS*(1-NPot) + (1-HS)*PPot.
+	EHS float64
+}
+
+const (
+	ahead = iota
+	tied
+	behind
+)
+
+type strengthSpot struct {
+	hole     CardSet
+	board    CardSet
+	opponent []weightedCombo
+}
+
+func newStrengthSpot(hole PersonalHand, board Board, opponent *Range) (*strengthSpot, error) {
+	holeSet, err := rankableHolding(hole, board)
+	if err != nil {
+		return nil, err
+	}
+	sp := &strengthSpot{hole: holeSet, board: board.CardSet()}
+	known := sp.hole.Union(sp.board)
+	if opponent == nil {
+		eachCombination(known.Complement().Cards(), 2, func(cs CardSet) {
+			sp.opponent = append(sp.opponent, weightedCombo{cards: cs, weight: 1})
+		})
+	} else {
+		for _, cs := range opponent.Exclude(known).sortedKeys() {
+			sp.opponent = append(sp.opponent, weightedCombo{cards: cs, weight: opponent.weights[cs]})
+		}
+	}
+	if len(sp.opponent) == 0 {
+		return nil, errors.New("poker: opponent range has no combos left")
+	}
+	return sp, nil
+}
+
+func compareValues(a, b HandValue) int {
+	switch {
+	case a > b:
+		return ahead
+	case a < b:
+		return behind
+	default:
+		return tied
+	}
+}
+
+// strength returns HS against the opponents that do not collide with dead.
+func (sp *strengthSpot) strength(board, dead CardSet) (float64, bool) {
+	mine := sp.hole.Union(board).Evaluate()
+	var score, total float64
+	for _, o := range sp.opponent {
+		if o.cards.Overlaps(dead) {
+			continue
+		}
+		switch compareValues(mine, o.cards.Union(board).Evaluate()) {
+		case ahead:
+			score += o.weight
+		case tied:
+			score += o.weight / 2
+		}
+		total += o.weight
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return score / total, true
+}
+
+// CalcHandStrength computes hand strength and hand potential of hole on the
+// board against the opponent range, or against every holding if opponent is
+// nil.
+func CalcHandStrength(hole PersonalHand, board Board, opponent *Range) (*HandStrength, error) {
+	sp, err := newStrengthSpot(hole, board, opponent)
+	if err != nil {
+		return nil, err
+	}
+
+	hs, _ := sp.strength(sp.board, 0)
+	result := &HandStrength{HS: hs, EHS: hs}
+	toCome := 5 - sp.board.Len()
+	if toCome == 0 {
+		return result, nil
+	}
+
+	var hp [3][3]float64
+	var hpTotal [3]float64
+	mineNow := sp.hole.Union(sp.board).Evaluate()
+	for _, o := range sp.opponent {
+		now := compareValues(mineNow, o.cards.Union(sp.board).Evaluate())
+		dead := sp.hole.Union(sp.board).Union(o.cards)
+		eachCombination(dead.Complement().Cards(), toCome, func(runout CardSet) {
+			board := sp.board.Union(runout)
+			later := compareValues(sp.hole.Union(board).Evaluate(), o.cards.Union(board).Evaluate())
+			hp[now][later] += o.weight
+			hpTotal[now] += o.weight
+		})
+	}
+
+	if d := hpTotal[behind] + hpTotal[tied]/2; d > 0 {
+		result.PPot = (hp[behind][ahead] + hp[behind][tied]/2 + hp[tied][ahead]/2) / d
+	}
+	if d := hpTotal[ahead] + hpTotal[tied]/2; d > 0 {
+		result.NPot = (hp[ahead][behind] + hp[tied][behind]/2 + hp[ahead][tied]/2) / d
+	}
+	result.EHS = hs*(1-result.NPot) + (1-hs)*result.PPot
+	return result, nil
+}
+
+// riverStrengths returns HS on the river for every runout of the board.
+func (sp *strengthSpot) riverStrengths() []float64 {
+	var strengths []float64
+	known := sp.hole.Union(sp.board)
+	eachCombination(known.Complement().Cards(), 5-sp.board.Len(), func(runout CardSet) {
+		if hs, ok := sp.strength(sp.board.Union(runout), runout); ok {
+			strengths = append(strengths, hs)
+		}
+	})
+	return strengths
+}
+
+// EHSSquared returns the expected square of the river hand strength, which
+// rewards hands with potential over hands of the same mean strength.
+func EHSSquared(hole PersonalHand, board Board, opponent *Range) (float64, error) {
+	sp, err := newStrengthSpot(hole, board, opponent)
+	if err != nil {
+		return 0, err
+	}
+	strengths := sp.riverStrengths()
+	if len(strengths) == 0 {
+		return 0, errors.New("poker: opponent range is blocked on every runout")
+	}
+	var sum float64
+	for _, hs := range strengths {
+		sum += hs * hs
+	}
+	return sum / float64(len(strengths)), nil
+}
+
+// StrengthHistogram splits the river hand strengths of every runout into
+// bins of equal width over [0, 1] and returns the share of runouts in each.
+func StrengthHistogram(hole PersonalHand, board Board, opponent *Range, bins int) ([]float64, error) {
+	if bins <= 0 {
+		return nil, fmt.Errorf("poker: invalid number of bins %d", bins)
+	}
+	sp, err := newStrengthSpot(hole, board, opponent)
+	if err != nil {
+		return nil, err
+	}
+	strengths := sp.riverStrengths()
+	if len(strengths) == 0 {
+		return nil, errors.New("poker: opponent range is blocked on every runout")
+	}
+	histogram := make([]float64, bins)
+	for _, hs := range strengths {
+		i := int(hs * float64(bins))
+		if i == bins {
+			i--
+		}
+		histogram[i] += 1 / float64(len(strengths))
+	}
+	return histogram, nil
+}
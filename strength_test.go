@@ -0,0 +1,135 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalcHandStrength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hole     string
+		board    string
+		opponent string
+		want     HandStrength
+		wantErr  bool
+	}{
+		{
+			// the example from Billings et al., "The challenge of poker"
+			name:  "ace queen on a jack high flop",
+			hole:  "Ad Qc",
+			board: "3h 4c Jh",
+			want:  HandStrength{HS: 0.585106, PPot: 0.208324, NPot: 0.273693, EHS: 0.511399},
+		},
+		{
+			name:  "royal flush",
+			hole:  "As Ks",
+			board: "Qs Js Ts",
+			want:  HandStrength{HS: 1, EHS: 1},
+		},
+		{
+			name:     "drawing dead against a range",
+			hole:     "Kc Kd",
+			board:    "Ah As 2c 7d 9h",
+			opponent: "AA",
+			want:     HandStrength{HS: 0},
+		},
+		{
+			name:     "range fully blocked",
+			hole:     "Kc Kd",
+			board:    "Ah As 2c",
+			opponent: "AhAs",
+			wantErr:  true,
+		},
+		{
+			name:    "preflop",
+			hole:    "Kc Kd",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var opponent *Range
+			if tt.opponent != "" {
+				opponent = mustRange(t, tt.opponent)
+			}
+			got, err := CalcHandStrength(PersonalHand{Cards: mustCards(t, tt.hole)}, Board{Cards: mustCards(t, tt.board)}, opponent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(got.HS-tt.want.HS) > 1e-6 || math.Abs(got.PPot-tt.want.PPot) > 1e-6 ||
+				math.Abs(got.NPot-tt.want.NPot) > 1e-6 || math.Abs(got.EHS-tt.want.EHS) > 1e-6 {
+				t.Fatalf("want %+v, but got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestCalcHandStrength_River(t *testing.T) {
+	t.Parallel()
+
+	hole := PersonalHand{Cards: mustCards(t, "Ts 9s")}
+	board := Board{Cards: mustCards(t, "Kh Qh Jc 3d 8s")}
+	got, err := CalcHandStrength(hole, board, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := HandPercentile(hole, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.HS != want || got.EHS != want || got.PPot != 0 || got.NPot != 0 {
+		t.Fatalf("want HS %v, but got %+v", want, *got)
+	}
+}
+
+func TestEHSSquared(t *testing.T) {
+	t.Parallel()
+
+	hole := PersonalHand{Cards: mustCards(t, "Ah Kh")}
+	board := Board{Cards: mustCards(t, "2h 7h 9c Jd")}
+	ehs2, err := EHSSquared(hole, board, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	histogram, err := StrengthHistogram(hole, board, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum, mean float64
+	for i, share := range histogram {
+		sum += share
+		mean += share * (float64(i) + 0.5) / 10
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("histogram sums to %v", sum)
+	}
+	if ehs2 < mean*mean-0.05 || ehs2 > 1 {
+		t.Fatalf("EHS squared %v is inconsistent with mean strength %v", ehs2, mean)
+	}
+	// nine flush cards give the nuts or close to it
+	if top := histogram[9]; top < 9.0/46-1e-9 {
+		t.Fatalf("want at least %v in the top bin, but got %v", 9.0/46, top)
+	}
+
+	if _, err := StrengthHistogram(hole, board, nil, 0); err == nil {
+		t.Fatalf("zero bins were accepted")
+	}
+
+	nuts, err := EHSSquared(PersonalHand{Cards: mustCards(t, "As Ks")}, Board{Cards: mustCards(t, "Qs Js Ts")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nuts != 1 {
+		t.Fatalf("want 1 for the nuts, but got %v", nuts)
+	}
+}
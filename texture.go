@@ -0,0 +1,144 @@
+package poker
+
+import "math/bits"
+
+type SuitTexture int
+
+const (
+	Rainbow SuitTexture = iota + 1
+	TwoTone
+	Monotone
+)
+
+var suitTextureToName = map[SuitTexture]string{
+	Rainbow:  "rainbow",
+	TwoTone:  "two-tone",
+	Monotone: "monotone",
+}
+
+func (t SuitTexture) String() string {
+	return suitTextureToName[t]
+}
+
+type HighCardCategory int
+
+const (
+	LowBoard HighCardCategory = iota + 1
+	MiddleBoard
+	BroadwayBoard
+	AceHighBoard
+)
+
+var highCardCategoryToName = map[HighCardCategory]string{
+	LowBoard:      "low",
+	MiddleBoard:   "middle",
+	BroadwayBoard: "broadway",
+	AceHighBoard:  "ace high",
+}
+
+func (c HighCardCategory) String() string {
+	return highCardCategoryToName[c]
+}
+
+type Texture struct {
+	Paired bool
+	Trips  bool
+	Suits  SuitTexture
+	// MaxSuitCount is the number of cards in the most common suit.
+	MaxSuitCount int
+	// Connectedness is the largest number of distinct board ranks that fit
+	// in a single straight.
+	Connectedness    int
+	StraightPossible bool
+	FlushPossible    bool
+	HighCard         CardRank
+	HighCardCategory HighCardCategory
+	// Nuts is the best hand any holding can make on the board.
+	Nuts *Hand
+}
+
+func AnalyzeBoard(b Board) (*Texture, error) {
+	cs, err := rankableBoard(b)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Texture{}
+	counts := map[CardRank]int{}
+	for _, c := range b.Cards {
+		counts[c.Rank]++
+		if c.Rank > t.HighCard {
+			t.HighCard = c.Rank
+		}
+	}
+	for _, n := range counts {
+		if n >= 2 {
+			t.Paired = true
+		}
+		if n >= 3 {
+			t.Trips = true
+		}
+	}
+
+	for s := 0; s < 4; s++ {
+		if n := bits.OnesCount16(cs.suitMask(s)); n > t.MaxSuitCount {
+			t.MaxSuitCount = n
+		}
+	}
+	switch {
+	case t.MaxSuitCount == len(b.Cards):
+		t.Suits = Monotone
+	case t.MaxSuitCount == 1:
+		t.Suits = Rainbow
+	default:
+		t.Suits = TwoTone
+	}
+	t.FlushPossible = t.MaxSuitCount >= 3
+
+	t.Connectedness = connectedness(cs.rankMask())
+	t.StraightPossible = t.Connectedness >= 3
+
+	switch {
+	case t.HighCard == Ace:
+		t.HighCardCategory = AceHighBoard
+	case t.HighCard >= Jack:
+		t.HighCardCategory = BroadwayBoard
+	case t.HighCard >= Nine:
+		t.HighCardCategory = MiddleBoard
+	default:
+		t.HighCardCategory = LowBoard
+	}
+
+	nuts, _ := nutHolding(cs)
+	hand, err := BestHand(cs.Union(nuts).Cards())
+	if err != nil {
+		return nil, err
+	}
+	t.Nuts = hand
+	return t, nil
+}
+
+func connectedness(ranks uint16) int {
+	// for ace to five straight
+	m := ranks<<1 | ranks>>12&1
+	best := 0
+	for low := 0; low <= 9; low++ {
+		if n := bits.OnesCount16(m >> uint(low) & 0x1f); n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+// nutHolding returns the two cards making the strongest hand on board and
+// the value of that hand.
+func nutHolding(board CardSet) (CardSet, HandValue) {
+	var nuts CardSet
+	var best HandValue
+	eachCombination(board.Complement().Cards(), 2, func(hole CardSet) {
+		if v := board.Union(hole).Evaluate(); v > best {
+			nuts, best = hole, v
+		}
+	})
+	return nuts, best
+}
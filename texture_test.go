@@ -0,0 +1,110 @@
+package poker
+
+import "testing"
+
+func TestAnalyzeBoard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		board    string
+		want     Texture
+		wantNuts HandRank
+		wantErr  bool
+	}{
+		{
+			name:  "dry rainbow",
+			board: "Kc 7d 2h",
+			want: Texture{
+				Suits: Rainbow, MaxSuitCount: 1, Connectedness: 1,
+				HighCard: King, HighCardCategory: BroadwayBoard,
+			},
+			wantNuts: ThreeOfAKind,
+		},
+		{
+			name:  "monotone connected",
+			board: "9h 8h 7h",
+			want: Texture{
+				Suits: Monotone, MaxSuitCount: 3, Connectedness: 3,
+				StraightPossible: true, FlushPossible: true,
+				HighCard: Nine, HighCardCategory: MiddleBoard,
+			},
+			wantNuts: StraightFlush,
+		},
+		{
+			name:  "paired two-tone",
+			board: "Ad As 5d 4c",
+			want: Texture{
+				Paired: true, Suits: TwoTone, MaxSuitCount: 2, Connectedness: 3,
+				StraightPossible: true,
+				HighCard:         Ace, HighCardCategory: AceHighBoard,
+			},
+			wantNuts: FourOfAKind,
+		},
+		{
+			name:  "trips",
+			board: "6d 6s 6c 3h 2h",
+			want: Texture{
+				Paired: true, Trips: true, Suits: TwoTone, MaxSuitCount: 2, Connectedness: 3,
+				StraightPossible: true,
+				HighCard:         Six, HighCardCategory: LowBoard,
+			},
+			wantNuts: FourOfAKind,
+		},
+		{
+			name:  "wheel cards",
+			board: "Ac 2d 4h",
+			want: Texture{
+				Suits: Rainbow, MaxSuitCount: 1, Connectedness: 3,
+				StraightPossible: true,
+				HighCard:         Ace, HighCardCategory: AceHighBoard,
+			},
+			wantNuts: Straight,
+		},
+		{
+			name:    "two cards",
+			board:   "Ac 2d",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := AnalyzeBoard(Board{Cards: mustCards(t, tt.board)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Nuts.Rank() != tt.wantNuts {
+				t.Fatalf("want nuts %s, but got %s", tt.wantNuts, got.Nuts.Rank())
+			}
+			got.Nuts = nil
+			if *got != tt.want {
+				t.Fatalf("want %+v, but got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBoard_Nuts(t *testing.T) {
+	t.Parallel()
+
+	got, err := AnalyzeBoard(Board{Cards: mustCards(t, "Kh Qh Jc 3d 8s")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ParseHand("Ah Kh Qh Jh Th")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Nuts.Rank() != Straight || got.Nuts.Compare(want) != Lose {
+		t.Fatalf("unexpected nuts %v", got.Nuts.Cards)
+	}
+	if got.Nuts.Cards[0].Rank != Ace {
+		t.Fatalf("want ace high straight, but got %v", got.Nuts.Cards)
+	}
+}
@@ -0,0 +1,440 @@
+// Package tournament runs multi-table Hold'em tournaments between agents.
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/yuzuy/poker"
+	"github.com/yuzuy/poker/holdem"
+)
+
+// Level is a stage of the blind schedule lasting Hands rounds. The last
+// level lasts until the end.
+type Level struct {
+	SmallBlind int
+	BigBlind   int
+	Ante       int
+	Hands      int
+}
+
+type Config struct {
+	Levels        []Level
+	TableSize     int
+	StartingStack int
+	// BuyIn is what each entry adds to the prize pool.
+	BuyIn int
+	// RebuyLevels is the number of levels during which busted players may
+	// rebuy. Add-ons are offered once when it ends.
+	RebuyLevels int
+	RebuyStack  int
+	RebuyCost   int
+	AddOnStack  int
+	AddOnCost   int
+	// Bounty is paid to whoever eliminates a player, on top of the prize
+	// pool.
+	Bounty int
+	// Payouts holds the share of the prize pool of each place.
+	Payouts []float64
+	// MaxRounds stops a tournament that does not end. It defaults to
+	// 100000.
+	MaxRounds int
+	Seed      int64
+}
+
+type Entrant struct {
+	Name  string
+	Agent holdem.Agent
+}
+
+// Rebuyer is implemented by agents deciding on rebuys and add-ons. Other
+// agents never take them.
+type Rebuyer interface {
+	Rebuy() bool
+	AddOn() bool
+}
+
+// Standing is how a player finished.
+type Standing struct {
+	Name     string
+	Place    int
+	Prize    int
+	Bounties int
+	Rebuys   int
+	AddOns   int
+}
+
+type Result struct {
+	// Standings holds the players from first to last.
+	Standings []Standing
+	PrizePool int
+	Rounds    int
+}
+
+type player struct {
+	Entrant
+	stack    int
+	rebuys   int
+	addOns   int
+	bounties int
+	place    int
+}
+
+// table holds the players in each seat, or -1 for an empty seat.
+type table struct {
+	seats  []int
+	button int
+}
+
+type Tournament struct {
+	cfg       Config
+	rnd       *rand.Rand
+	players   []*player
+	tables    []*table
+	remaining int
+	rounds    int
+	prizePool int
+	addOns    bool
+}
+
+func New(cfg Config, entrants []Entrant) (*Tournament, error) {
+	if len(entrants) < 2 {
+		return nil, errors.New("tournament: need at least 2 entrants")
+	}
+	if len(cfg.Levels) == 0 {
+		return nil, errors.New("tournament: no levels")
+	}
+	if cfg.TableSize < 2 || cfg.StartingStack <= 0 {
+		return nil, errors.New("tournament: invalid table size or starting stack")
+	}
+	total := 0.0
+	for _, p := range cfg.Payouts {
+		if p < 0 {
+			return nil, fmt.Errorf("tournament: invalid payout %v", p)
+		}
+		total += p
+	}
+	if total > 1+1e-9 {
+		return nil, errors.New("tournament: payouts add up to more than the prize pool")
+	}
+	if cfg.MaxRounds <= 0 {
+		cfg.MaxRounds = 100000
+	}
+
+	t := &Tournament{cfg: cfg, rnd: rand.New(rand.NewSource(cfg.Seed)), remaining: len(entrants)}
+	for _, e := range entrants {
+		t.players = append(t.players, &player{Entrant: e, stack: cfg.StartingStack})
+		t.prizePool += cfg.BuyIn
+	}
+
+	order := t.rnd.Perm(len(entrants))
+	n := (len(entrants) + cfg.TableSize - 1) / cfg.TableSize
+	for i := 0; i < n; i++ {
+		t.tables = append(t.tables, &table{seats: make([]int, cfg.TableSize)})
+		for s := range t.tables[i].seats {
+			t.tables[i].seats[s] = -1
+		}
+	}
+	for i, p := range order {
+		t.tables[i%n].seats[i/n] = p
+	}
+	return t, nil
+}
+
+func (t *Tournament) Done() bool {
+	return t.remaining < 2
+}
+
+// Level returns the level of the next round.
+func (t *Tournament) Level() Level {
+	return t.cfg.Levels[t.levelIndex()]
+}
+
+func (t *Tournament) levelIndex() int {
+	hands := t.rounds
+	for i, l := range t.cfg.Levels {
+		if hands < l.Hands || i == len(t.cfg.Levels)-1 {
+			return i
+		}
+		hands -= l.Hands
+	}
+	return len(t.cfg.Levels) - 1
+}
+
+// Tables returns the names of the players at each table by seat, with
+// empty seats left blank.
+func (t *Tournament) Tables() [][]string {
+	var tables [][]string
+	for _, tb := range t.tables {
+		names := make([]string, len(tb.seats))
+		for s, p := range tb.seats {
+			if p >= 0 {
+				names[s] = t.players[p].Name
+			}
+		}
+		tables = append(tables, names)
+	}
+	return tables
+}
+
+// Run plays rounds until one player is left.
+func (t *Tournament) Run() (*Result, error) {
+	for !t.Done() {
+		if t.rounds >= t.cfg.MaxRounds {
+			return nil, fmt.Errorf("tournament: not over after %d rounds", t.rounds)
+		}
+		if err := t.PlayRound(); err != nil {
+			return nil, err
+		}
+	}
+	return t.Result(), nil
+}
+
+// PlayRound deals one hand at every table, then breaks and balances the
+// tables.
+func (t *Tournament) PlayRound() error {
+	if t.Done() {
+		return errors.New("tournament: the tournament is over")
+	}
+	level := t.levelIndex()
+	if !t.addOns && level >= t.cfg.RebuyLevels && t.cfg.RebuyLevels > 0 {
+		t.addOns = true
+		t.offerAddOns()
+	}
+	for _, tb := range t.tables {
+		if err := t.playHand(tb, level); err != nil {
+			return err
+		}
+	}
+	t.rounds++
+	t.rebalance()
+	return nil
+}
+
+func (t *Tournament) offerAddOns() {
+	for _, p := range t.players {
+		if r, ok := p.Agent.(Rebuyer); ok && p.place == 0 && t.cfg.AddOnStack > 0 && r.AddOn() {
+			p.stack += t.cfg.AddOnStack
+			p.addOns++
+			t.prizePool += t.cfg.AddOnCost
+		}
+	}
+}
+
+func (t *Tournament) playHand(tb *table, level int) error {
+	seated := 0
+	for _, p := range tb.seats {
+		if p >= 0 {
+			seated++
+		}
+	}
+	if seated < 2 {
+		return nil
+	}
+
+	stacks := make([]int, len(tb.seats))
+	agents := make([]holdem.Agent, len(tb.seats))
+	for s, p := range tb.seats {
+		if p >= 0 {
+			stacks[s] = t.players[p].stack
+			agents[s] = t.players[p].Agent
+		}
+	}
+	for tb.seats[tb.button] < 0 {
+		tb.button = (tb.button + 1) % len(tb.seats)
+	}
+
+	l := t.cfg.Levels[level]
+	deck := poker.FullCardSet.Deck()
+	deck.Shuffle(t.rnd)
+	g, err := holdem.NewGame(holdem.Config{SmallBlind: l.SmallBlind, BigBlind: l.BigBlind, Ante: l.Ante}, stacks, tb.button, deck)
+	if err != nil {
+		return err
+	}
+	res := g.Play(agents)
+
+	var busted []int
+	for s, p := range tb.seats {
+		if p < 0 {
+			continue
+		}
+		t.players[p].stack = res.Stacks[s]
+		if res.Stacks[s] == 0 {
+			busted = append(busted, s)
+		}
+	}
+	// bigger stacks at the start of the hand finish higher
+	sort.SliceStable(busted, func(i, j int) bool { return stacks[busted[i]] < stacks[busted[j]] })
+	for _, s := range busted {
+		p := t.players[tb.seats[s]]
+		if r, ok := p.Agent.(Rebuyer); ok && level < t.cfg.RebuyLevels && t.cfg.RebuyStack > 0 && r.Rebuy() {
+			p.stack = t.cfg.RebuyStack
+			p.rebuys++
+			t.prizePool += t.cfg.RebuyCost
+			continue
+		}
+		t.payBounty(tb, s, res)
+		p.place = t.remaining
+		t.remaining--
+		tb.seats[s] = -1
+	}
+
+	for {
+		tb.button = (tb.button + 1) % len(tb.seats)
+		if tb.seats[tb.button] >= 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// payBounty pays the bounty of the player busted in seat to the winners of
+// the last pot it played for.
+func (t *Tournament) payBounty(tb *table, seat int, res *holdem.Result) {
+	if t.cfg.Bounty == 0 {
+		return
+	}
+	var winners []int
+	for _, pot := range res.Pots {
+		for _, e := range pot.Eligible {
+			if e == seat {
+				winners = pot.Winners
+			}
+		}
+	}
+	if len(winners) == 0 {
+		return
+	}
+	share, odd := t.cfg.Bounty/len(winners), t.cfg.Bounty%len(winners)
+	for i, w := range winners {
+		p := t.players[tb.seats[w]]
+		p.bounties += share
+		if i < odd {
+			p.bounties++
+		}
+	}
+}
+
+// rebalance breaks a table when the others have room for its players, then
+// moves players from the fullest tables to the emptiest ones. The player
+// moved is the one due the big blind.
+func (t *Tournament) rebalance() {
+	for len(t.tables) > 1 && t.remaining <= (len(t.tables)-1)*t.cfg.TableSize {
+		smallest := 0
+		for i, tb := range t.tables {
+			if count(tb) < count(t.tables[smallest]) {
+				smallest = i
+			}
+		}
+		broken := t.tables[smallest]
+		t.tables = append(t.tables[:smallest], t.tables[smallest+1:]...)
+		for _, p := range broken.seats {
+			if p >= 0 {
+				t.seat(p)
+			}
+		}
+	}
+
+	for {
+		most, least := 0, 0
+		for i, tb := range t.tables {
+			if count(tb) > count(t.tables[most]) {
+				most = i
+			}
+			if count(tb) < count(t.tables[least]) {
+				least = i
+			}
+		}
+		if count(t.tables[most])-count(t.tables[least]) < 2 {
+			return
+		}
+		tb := t.tables[most]
+		s := nextSeat(tb, nextSeat(tb, tb.button))
+		p := tb.seats[s]
+		tb.seats[s] = -1
+		t.seat(p)
+	}
+}
+
+// seat puts p in an empty seat of the table with the fewest players.
+func (t *Tournament) seat(p int) {
+	least := 0
+	for i, tb := range t.tables {
+		if count(tb) < count(t.tables[least]) {
+			least = i
+		}
+	}
+	tb := t.tables[least]
+	empty := []int{}
+	for s, q := range tb.seats {
+		if q < 0 {
+			empty = append(empty, s)
+		}
+	}
+	tb.seats[empty[t.rnd.Intn(len(empty))]] = p
+}
+
+func count(tb *table) int {
+	n := 0
+	for _, p := range tb.seats {
+		if p >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// nextSeat returns the occupied seat after s.
+func nextSeat(tb *table, s int) int {
+	for {
+		s = (s + 1) % len(tb.seats)
+		if tb.seats[s] >= 0 {
+			return s
+		}
+	}
+}
+
+// Result returns the standings, or nil until the tournament is over.
+func (t *Tournament) Result() *Result {
+	if !t.Done() {
+		return nil
+	}
+	r := &Result{PrizePool: t.prizePool, Rounds: t.rounds}
+	for _, p := range t.players {
+		place := p.place
+		if place == 0 {
+			place = 1
+		}
+		r.Standings = append(r.Standings, Standing{
+			Name: p.Name, Place: place, Bounties: p.bounties, Rebuys: p.rebuys, AddOns: p.addOns,
+		})
+	}
+	sort.Slice(r.Standings, func(i, j int) bool { return r.Standings[i].Place < r.Standings[j].Place })
+
+	paid := 0
+	for i, share := range t.cfg.Payouts {
+		if i >= len(r.Standings) {
+			break
+		}
+		r.Standings[i].Prize = int(share * float64(t.prizePool))
+		paid += r.Standings[i].Prize
+	}
+	if len(t.cfg.Payouts) > 0 {
+		// what rounding leaves goes to the winner
+		total := 0.0
+		for _, share := range t.cfg.Payouts[:min(len(t.cfg.Payouts), len(r.Standings))] {
+			total += share
+		}
+		r.Standings[0].Prize += int(total*float64(t.prizePool)+0.5) - paid
+	}
+	return r
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,187 @@
+package tournament
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yuzuy/poker/holdem"
+	"github.com/yuzuy/poker/holdem/bot"
+)
+
+var levels = []Level{
+	{SmallBlind: 10, BigBlind: 20, Hands: 10},
+	{SmallBlind: 25, BigBlind: 50, Ante: 5, Hands: 10},
+	{SmallBlind: 100, BigBlind: 200, Ante: 25},
+}
+
+// rebuyer takes every rebuy and add-on.
+type rebuyer struct {
+	holdem.Agent
+}
+
+func (rebuyer) Rebuy() bool { return true }
+func (rebuyer) AddOn() bool { return true }
+
+func entrants(n int, agent func(i int) holdem.Agent) []Entrant {
+	var es []Entrant
+	for i := 0; i < n; i++ {
+		es = append(es, Entrant{Name: fmt.Sprintf("p%d", i), Agent: agent(i)})
+	}
+	return es
+}
+
+func mixed(i int) holdem.Agent {
+	switch i % 3 {
+	case 0:
+		return bot.NewTightAggressive()
+	case 1:
+		return bot.CallingStation{}
+	default:
+		return bot.NewRandom(rand.New(rand.NewSource(int64(i))))
+	}
+}
+
+func TestTournament_Run(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		Levels: levels, TableSize: 6, StartingStack: 1000,
+		BuyIn: 100, Bounty: 20, Payouts: []float64{0.5, 0.3, 0.2}, Seed: 1,
+	}
+	tr, err := New(cfg, entrants(14, mixed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := tr.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PrizePool != 1400 {
+		t.Errorf("want is prize pool 1400, but got %d", r.PrizePool)
+	}
+	prizes, bounties := 0, 0
+	for i, s := range r.Standings {
+		if s.Place != i+1 {
+			t.Errorf("want is place %d, but got %d", i+1, s.Place)
+		}
+		prizes += s.Prize
+		bounties += s.Bounties
+	}
+	if diff := cmp.Diff([]int{r.Standings[0].Prize, r.Standings[1].Prize, r.Standings[2].Prize, r.Standings[3].Prize}, []int{700, 420, 280, 0}); diff != "" {
+		t.Errorf("want and got are different(-got +want): %s", diff)
+	}
+	if prizes != r.PrizePool {
+		t.Errorf("want is %d paid, but got %d", r.PrizePool, prizes)
+	}
+	if bounties != 13*20 {
+		t.Errorf("want is %d paid in bounties, but got %d", 13*20, bounties)
+	}
+}
+
+func TestTournament_Rebuy(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		Levels: levels, TableSize: 9, StartingStack: 500, BuyIn: 10,
+		RebuyLevels: 2, RebuyStack: 500, RebuyCost: 10, AddOnStack: 1000, AddOnCost: 10,
+		Payouts: []float64{1}, Seed: 2,
+	}
+	tr, err := New(cfg, entrants(4, func(i int) holdem.Agent { return rebuyer{mixed(i)} }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := tr.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuys, addOns := 0, 0
+	for _, s := range r.Standings {
+		rebuys += s.Rebuys
+		addOns += s.AddOns
+	}
+	if rebuys == 0 {
+		t.Error("nobody rebought")
+	}
+	if want := 40 + 10*(rebuys+addOns); r.PrizePool != want {
+		t.Errorf("want is prize pool %d, but got %d", want, r.PrizePool)
+	}
+	if r.Standings[0].Prize != r.PrizePool {
+		t.Errorf("want is %d for the winner, but got %d", r.PrizePool, r.Standings[0].Prize)
+	}
+}
+
+func TestTournament_PlayRound_Balance(t *testing.T) {
+	t.Parallel()
+	cfg := Config{Levels: levels, TableSize: 4, StartingStack: 300, Seed: 3}
+	tr, err := New(cfg, entrants(11, mixed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !tr.Done() {
+		if err := tr.PlayRound(); err != nil {
+			t.Fatal(err)
+		}
+		most, least, players := 0, cfg.TableSize, 0
+		for _, tb := range tr.Tables() {
+			n := 0
+			for _, name := range tb {
+				if name != "" {
+					n++
+				}
+			}
+			if n > most {
+				most = n
+			}
+			least, players = min(least, n), players+n
+		}
+		if most-least > 1 {
+			t.Fatalf("tables %v are not balanced", tr.Tables())
+		}
+		if want := (players + cfg.TableSize - 1) / cfg.TableSize; len(tr.Tables()) != want {
+			t.Fatalf("%d players: want is %d tables, but got %d", players, want, len(tr.Tables()))
+		}
+	}
+	if err := tr.PlayRound(); err == nil {
+		t.Error("PlayRound() after the end: want error but got nil")
+	}
+}
+
+func TestTournament_Level(t *testing.T) {
+	t.Parallel()
+	tr, err := New(Config{Levels: levels, TableSize: 2, StartingStack: 100000}, entrants(2, mixed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for round, want := range []int{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 50} {
+		if got := tr.Level().BigBlind; got != want {
+			t.Errorf("round %d: want is big blind %d, but got %d", round, want, got)
+		}
+		if err := tr.PlayRound(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNew_Error(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		cfg      Config
+		entrants int
+	}{
+		{name: "one entrant", cfg: Config{Levels: levels, TableSize: 9, StartingStack: 100}, entrants: 1},
+		{name: "no levels", cfg: Config{TableSize: 9, StartingStack: 100}, entrants: 2},
+		{name: "table of one", cfg: Config{Levels: levels, TableSize: 1, StartingStack: 100}, entrants: 2},
+		{name: "no stack", cfg: Config{Levels: levels, TableSize: 9}, entrants: 2},
+		{name: "payouts", cfg: Config{Levels: levels, TableSize: 9, StartingStack: 100, Payouts: []float64{0.7, 0.5}}, entrants: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := New(tt.cfg, entrants(tt.entrants, mixed)); err == nil {
+				t.Error("want error but got nil")
+			}
+		})
+	}
+}